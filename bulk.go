@@ -0,0 +1,258 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"cmp"
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const defaultConcurrency = 16
+
+// bulkOptions are the per-call settings of operations on many files.
+type bulkOptions struct {
+	concurrency int
+	failFast    bool
+	partial     bool
+}
+
+// bulkResult is the result of a bulk operation for a single key.
+type bulkResult[T any] struct {
+	key   string
+	value T
+	err   error
+}
+
+// concurrencyLimit returns the per-call concurrency if set, otherwise the concurrency of the client.
+func (o *bulkOptions) concurrencyLimit(clientConcurrency int) int {
+	return max(cmp.Or(o.concurrency, clientConcurrency, defaultConcurrency), 1)
+}
+
+// runBulk calls fn for every listed key, with at most limit calls running at the same time.
+// The results are sorted by key. With failFast, the context of the running calls is cancelled
+// on the first error and no further calls are started.
+func runBulk[T any](
+	ctx context.Context,
+	keys func(ctx context.Context) iter.Seq2[string, error],
+	limit int,
+	failFast bool,
+	fn func(ctx context.Context, key string) (T, error),
+) ([]bulkResult[T], error) {
+	bulkCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	semaphore := make(chan struct{}, limit)
+	wg := new(sync.WaitGroup)
+	mtx := new(sync.Mutex)
+
+	results := make([]bulkResult[T], 0)
+
+	var listErr error
+
+	for key, err := range keys(bulkCtx) {
+		if err != nil {
+			if bulkCtx.Err() == nil {
+				listErr = err
+			}
+
+			break
+		}
+
+		select {
+		case semaphore <- struct{}{}:
+		case <-bulkCtx.Done():
+		}
+
+		if bulkCtx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			value, err := fn(bulkCtx, key)
+
+			mtx.Lock()
+			results = append(results, bulkResult[T]{key: key, value: value, err: err})
+			mtx.Unlock()
+
+			if err != nil && failFast {
+				cancel()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	slices.SortFunc(results, func(a, b bulkResult[T]) int {
+		return strings.Compare(a.key, b.key)
+	})
+
+	if listErr != nil {
+		return results, listErr
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err //nolint:wrapcheck // wrapped by the caller
+	}
+
+	return results, nil
+}
+
+// collectResults returns the values of all keys processed successfully.
+// If any of the keys failed, a BulkError is returned. Unless partial is set, the values are discarded on failure.
+func collectResults[T any](results []bulkResult[T], err error, operation Operation, partial bool) ([]T, error) {
+	values := make([]T, 0, len(results))
+	bulkErr := new(BulkError)
+
+	for i := range results {
+		if results[i].err != nil {
+			bulkErr.Failures = append(bulkErr.Failures, &KeyError{
+				Key:       results[i].key,
+				Operation: operation,
+				Err:       results[i].err,
+			})
+
+			continue
+		}
+
+		values = append(values, results[i].value)
+		bulkErr.Succeeded = append(bulkErr.Succeeded, results[i].key)
+	}
+
+	if err == nil && len(bulkErr.Failures) > 0 {
+		err = bulkErr
+	}
+
+	if err != nil && !partial {
+		return nil, err
+	}
+
+	return values, err
+}
+
+// closeFiles closes all opened files of the results.
+func closeFiles(results []bulkResult[File]) {
+	for i := range results {
+		if results[i].value != nil {
+			results[i].value.Close()
+		}
+	}
+}
+
+// listKeysFunc lists the keys of all objects under the given prefix.
+type listKeysFunc func(ctx context.Context, prefix string, recursive bool) iter.Seq2[string, error]
+
+type getFileFunc func(ctx context.Context, path string, options ...GetOption) (File, error)
+
+type getFileInfoFunc func(ctx context.Context, path string, options ...FileInfoOption) (*FileInfo, error)
+
+type downloadFileFunc func(ctx context.Context, path, localPath string, options ...DownloadOption) error
+
+// getDirectory opens all files under the given path. Unless partial is set, the opened files are closed on failure.
+func getDirectory(
+	ctx context.Context,
+	listKeys listKeysFunc,
+	clientConcurrency int,
+	path string,
+	getFile getFileFunc,
+	options ...GetDirectoryOption,
+) ([]File, error) {
+	opts := new(getDirectoryOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	results, err := runBulk(
+		ctx,
+		func(ctx context.Context) iter.Seq2[string, error] {
+			return listKeys(ctx, path, true)
+		},
+		opts.concurrencyLimit(clientConcurrency),
+		opts.failFast,
+		func(ctx context.Context, key string) (File, error) {
+			return getFile(ctx, key, WithClientGetOptions(opts.clientOptions))
+		},
+	)
+
+	files, err := collectResults(results, err, OperationGetFile, opts.partial)
+	if err != nil && !opts.partial {
+		closeFiles(results)
+	}
+
+	return files, err
+}
+
+// getDirectoryInfos returns the file infos of all files under the given path.
+func getDirectoryInfos(
+	ctx context.Context,
+	listKeys listKeysFunc,
+	clientConcurrency int,
+	path string,
+	getFileInfo getFileInfoFunc,
+	options ...GetDirectoryOption,
+) ([]*FileInfo, error) {
+	opts := new(getDirectoryOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	results, err := runBulk(
+		ctx,
+		func(ctx context.Context) iter.Seq2[string, error] {
+			return listKeys(ctx, path, true)
+		},
+		opts.concurrencyLimit(clientConcurrency),
+		opts.failFast,
+		func(ctx context.Context, key string) (*FileInfo, error) {
+			return getFileInfo(ctx, key)
+		},
+	)
+
+	return collectResults(results, err, OperationGetFileInfo, opts.partial)
+}
+
+// downloadDirectory downloads all files under the given path into the local folder, keeping the folder structure.
+func downloadDirectory(
+	ctx context.Context,
+	listKeys listKeysFunc,
+	clientConcurrency int,
+	path, localPath string,
+	recursive bool,
+	downloadFile downloadFileFunc,
+	options ...DownloadOption,
+) error {
+	opts := new(downloadOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	// every file is downloaded in its current version
+	fileOptions := append(slices.Clone(options), withoutDownloadVersionID())
+
+	results, err := runBulk(
+		ctx,
+		func(ctx context.Context) iter.Seq2[string, error] {
+			return listKeys(ctx, path, recursive)
+		},
+		opts.concurrencyLimit(clientConcurrency),
+		opts.failFast,
+		func(ctx context.Context, key string) (struct{}, error) {
+			fileName := strings.TrimPrefix(key, path+"/")
+
+			return struct{}{}, downloadFile(ctx, key, localPath+"/"+fileName, fileOptions...)
+		},
+	)
+
+	_, err = collectResults(results, err, OperationDownloadFile, false)
+
+	return err
+}
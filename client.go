@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"sync"
+	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
@@ -12,10 +14,18 @@ import (
 const name = "s3"
 
 type client struct {
-	minioClient *minio.Client
-	bucketName  string
-	urlValues   url.Values
-	cancelFunc  context.CancelFunc
+	minioClient    *minio.Client
+	linkClient     *minio.Client // signs presigned links, connected to the public endpoint if set
+	publicEndpoint *publicEndpoint
+	encryption     *Encryption  // default encryption of all requests, overridden per call
+	keyProvider    KeyProvider  // wraps the data keys of client-side encrypted files
+	retryPolicy    *RetryPolicy // replaces the internal retries of minio-go if set
+	bucketName     string
+	urlValues      url.Values
+	cancelFunc     context.CancelFunc
+	concurrency    int
+	healthCheck    time.Duration // interval of the health check, disabled if 0
+	lifecycleMtx   sync.Mutex
 	integritySettings
 }
 
@@ -28,44 +38,80 @@ func NewClient(details *ClientDetails, options ...ClientOption) (Client, error)
 	}
 
 	client := &client{
-		bucketName: details.BucketName,
-		urlValues:  make(url.Values),
-		integritySettings: integritySettings{
-			useIntegrityCRC32C: true,
-			useIntegrityMD5:    false,
-		},
+		bucketName:        details.BucketName,
+		urlValues:         make(url.Values),
+		concurrency:       defaultConcurrency,
+		integritySettings: defaultIntegritySettings(),
 	}
 
-	var err error
+	for i := range options {
+		if err := options[i](client); err != nil {
+			return nil, fmt.Errorf(errMessage, err)
+		}
+	}
 
-	client.minioClient, err = minio.New(details.Host, &minio.Options{
+	minioOptions := &minio.Options{
 		Creds:  credentials.NewStaticV4(details.AccessKey, details.AccessSecret, ""),
 		Secure: details.Secure,
-	})
+	}
+
+	if client.retryPolicy != nil {
+		minioOptions.MaxRetries = 1
+	}
+
+	var err error
+
+	client.minioClient, err = minio.New(details.Host, minioOptions)
 	if err != nil {
 		return nil, fmt.Errorf(errMessage, err)
 	}
 
-	for i := range options {
-		if err := options[i](client); err != nil {
-			return nil, fmt.Errorf(errMessage, err)
+	if client.healthCheck > 0 {
+		client.cancelFunc, err = client.minioClient.HealthCheck(client.healthCheck)
+		if err != nil {
+			return nil, fmt.Errorf(errMessage, fmt.Errorf("failed to enable health check: %w", err))
 		}
 	}
 
 	exists, err := client.minioClient.BucketExists(context.Background(), details.BucketName)
 	if err != nil {
-		return nil, fmt.Errorf(errMessage, err)
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
 	}
 
 	if !exists {
 		return nil, fmt.Errorf(errMessage, &BucketDoesNotExistError{details.BucketName})
 	}
 
+	client.linkClient, err = client.newLinkClient(details)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
 	client.urlValues.Set("response-content-disposition", "inline")
 
 	return client, nil
 }
 
+// newLinkClient returns the client signing presigned links. Links handed to browsers have to be signed
+// for the public endpoint, since the host is part of the signature.
+func (c *client) newLinkClient(details *ClientDetails) (*minio.Client, error) {
+	if c.publicEndpoint == nil {
+		return c.minioClient, nil
+	}
+
+	// the region is set, so presigning does not request the bucket location from the public endpoint
+	region, err := c.minioClient.GetBucketLocation(context.Background(), details.BucketName)
+	if err != nil {
+		return nil, handleClientError(err)
+	}
+
+	return minio.New(c.publicEndpoint.host, &minio.Options{
+		Creds:  credentials.NewStaticV4(details.AccessKey, details.AccessSecret, ""),
+		Secure: c.publicEndpoint.secure,
+		Region: region,
+	})
+}
+
 func (c *client) Close() {
 	if c.cancelFunc != nil {
 		c.cancelFunc()
@@ -0,0 +1,182 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	compressionChunkSize = 32 << 10
+
+	keyCompression     = "Client-Compression"
+	keyCompressionSize = "Client-Compression-Original-Size"
+)
+
+// Compression is the encoding compressing the content of a file.
+type Compression string
+
+const (
+	// CompressionNone stores the content as it is.
+	CompressionNone Compression = ""
+	// CompressionGzip compresses the content using gzip.
+	CompressionGzip Compression = "gzip"
+	// CompressionZstd compresses the content using zstandard, which is faster and compresses better than gzip.
+	CompressionZstd Compression = "zstd"
+)
+
+// compressedContent describes the compression of a file, as stored in its metadata.
+type compressedContent struct {
+	compression Compression
+	size        int64 // size of the uncompressed content, -1 if it has not been recorded
+}
+
+// takeCompression returns the compression of the file and removes its keys from the metadata of the file info,
+// or nil if the file is not compressed. The size of the file info is set to the size of the uncompressed content.
+// Unknown compressions were not written by the client, so those files are read as they are.
+func takeCompression(info *FileInfo) *compressedContent {
+	compressed := &compressedContent{
+		compression: Compression(info.MetaData[keyCompression]),
+		size:        -1,
+	}
+
+	if compressed.compression != CompressionGzip && compressed.compression != CompressionZstd {
+		return nil
+	}
+
+	if size, err := strconv.ParseInt(info.MetaData[keyCompressionSize], 10, 64); err == nil && size >= 0 {
+		compressed.size = size
+	}
+
+	delete(info.MetaData, keyCompression)
+	delete(info.MetaData, keyCompressionSize)
+
+	info.Size = compressed.size
+	info.Compression = compressed.compression
+
+	return compressed
+}
+
+func (c *compressedContent) metaData() map[string]string {
+	metaData := map[string]string{keyCompression: string(c.compression)}
+
+	if c.size >= 0 {
+		metaData[keyCompressionSize] = strconv.FormatInt(c.size, 10)
+	}
+
+	return metaData
+}
+
+// compressingReader compresses the content while it is read and counts the size of the uncompressed content.
+type compressingReader struct {
+	source  io.Reader
+	encoder io.WriteCloser
+	chunk   []byte
+	pending bytes.Buffer
+	size    int64
+	done    bool
+}
+
+func newCompressingReader(content io.Reader, compression Compression) (*compressingReader, error) {
+	reader := &compressingReader{
+		source: content,
+		chunk:  make([]byte, compressionChunkSize),
+	}
+
+	switch compression {
+	case CompressionGzip:
+		reader.encoder = gzip.NewWriter(&reader.pending)
+	case CompressionZstd:
+		encoder, err := zstd.NewWriter(&reader.pending, zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		reader.encoder = encoder
+	default:
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidCompression, compression)
+	}
+
+	return reader, nil
+}
+
+// Read implements the io.Reader interface.
+func (r *compressingReader) Read(p []byte) (int, error) {
+	for r.pending.Len() == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.compressChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	return r.pending.Read(p)
+}
+
+func (r *compressingReader) compressChunk() error {
+	n, err := r.source.Read(r.chunk)
+
+	r.size += int64(n)
+
+	if n > 0 {
+		if _, err := r.encoder.Write(r.chunk[:n]); err != nil {
+			return err
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		r.done = true
+
+		return r.encoder.Close()
+	}
+
+	return err
+}
+
+// decompressingReader decompresses the content while it is read.
+type decompressingReader struct {
+	io.Reader
+	decoder io.Closer
+	content io.Closer
+}
+
+func newDecompressingReader(content io.ReadCloser, compression Compression) (io.ReadCloser, error) {
+	switch compression {
+	case CompressionGzip:
+		decoder, err := gzip.NewReader(content)
+		if err != nil {
+			return nil, err
+		}
+
+		return &decompressingReader{Reader: decoder, decoder: decoder, content: content}, nil
+	case CompressionZstd:
+		decoder, err := zstd.NewReader(content, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+
+		reader := decoder.IOReadCloser()
+
+		return &decompressingReader{Reader: reader, decoder: reader, content: content}, nil
+	default:
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidCompression, compression)
+	}
+}
+
+// Close implements the io.Closer interface.
+func (r *decompressingReader) Close() error {
+	decoderErr := r.decoder.Close()
+
+	if err := r.content.Close(); err != nil {
+		return err
+	}
+
+	return decoderErr
+}
@@ -0,0 +1,135 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Compression(t *testing.T) {
+	t.Parallel()
+
+	testCompression(t, getS3Client, "test-compression")
+}
+
+func testCompression(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, testFolder string) {
+	t.Helper()
+
+	ctx := context.Background()
+	folder := testFolder + "/" + uuid.NewString()
+
+	provider, err := s3.NewStaticKeyProvider("current", map[string][]byte{"current": bytes.Repeat([]byte{0x01}, 32)})
+	require.NoError(t, err)
+
+	clients := map[string]s3.Client{
+		"plain":     newClient(t, s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true)),
+		"encrypted": newClient(t, s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true), s3.WithClientSideEncryption(provider)),
+	}
+
+	content := []byte(strings.Repeat("id,name,amount\n42,export,1000\n", 5000))
+	size := int64(len(content))
+
+	expectedChecksum, err := s3.GenerateCheckSum(s3.ChecksumAlgorithmSHA256, bytes.NewReader(content))
+	require.NoError(t, err)
+
+	for clientName, s3Client := range clients {
+		for _, compression := range []s3.Compression{s3.CompressionGzip, s3.CompressionZstd} {
+			filePath := folder + "/" + clientName + "/" + string(compression)
+
+			upload := s3.NewUpload(bytes.NewReader(content), &size, filePath, "text/csv", map[string]string{"Export": "daily"})
+
+			uploaded, err := s3Client.UploadFile(ctx, upload, s3.WithCompression(compression))
+			require.NoError(t, err)
+			require.Equal(t, size, uploaded.Size, filePath)
+			require.Equal(t, expectedChecksum, uploaded.Checksum(s3.ChecksumAlgorithmSHA256), filePath)
+
+			file, err := s3Client.GetFile(ctx, filePath, s3.WithAutoIntegrityCheck())
+			require.NoError(t, err)
+
+			fileContent, err := file.Bytes()
+			require.NoError(t, err)
+			require.Equal(t, content, fileContent, filePath)
+			require.Equal(t, size, file.Info().Size, filePath)
+			require.Equal(t, compression, file.Info().Compression, filePath)
+			require.Equal(t, map[string]string{"Export": "daily"}, file.Info().MetaData, filePath)
+
+			info, err := s3Client.GetFileInfo(ctx, filePath)
+			require.NoError(t, err)
+			require.Equal(t, size, info.Size, filePath)
+			require.Equal(t, expectedChecksum, info.Checksum(s3.ChecksumAlgorithmSHA256), filePath)
+
+			localPath := filepath.Join(t.TempDir(), "downloaded")
+
+			err = s3Client.DownloadFile(ctx, filePath, localPath)
+			require.NoError(t, err)
+
+			downloaded, err := os.ReadFile(localPath)
+			require.NoError(t, err)
+			require.Equal(t, content, downloaded, filePath)
+
+			// listings without full file info report the size of the stored content
+			for listed, err := range s3Client.ListFiles(ctx, filePath) {
+				require.NoError(t, err)
+				require.Less(t, listed.Size, size/5, filePath)
+			}
+
+			copied, err := s3Client.CopyFile(ctx, filePath, filePath+"-copy", s3.WithCopyMetaData(map[string]string{"Export": "copy"}))
+			require.NoError(t, err)
+			require.Equal(t, size, copied.Size, filePath)
+
+			file, err = s3Client.GetFile(ctx, filePath+"-copy", s3.WithAutoIntegrityCheck())
+			require.NoError(t, err)
+
+			fileContent, err = file.Bytes()
+			require.NoError(t, err)
+			require.Equal(t, content, fileContent, filePath)
+			require.Equal(t, map[string]string{"Export": "copy"}, file.Info().MetaData, filePath)
+		}
+	}
+
+	s3Client := clients["plain"]
+
+	stream := s3.NewStreamUpload(strings.NewReader(""), folder+"/empty", contentType, nil)
+
+	uploaded, err := s3Client.UploadStream(ctx, stream, s3.WithCompression(s3.CompressionZstd))
+	require.NoError(t, err)
+	require.Zero(t, uploaded.Size)
+
+	file, err := s3Client.GetFile(ctx, folder+"/empty")
+	require.NoError(t, err)
+
+	fileContent, err := file.Bytes()
+	require.NoError(t, err)
+	require.Empty(t, fileContent)
+	require.Zero(t, file.Info().Size)
+
+	_, err = s3Client.UploadFile(ctx,
+		s3.NewUpload(bytes.NewReader(content), &size, folder+"/invalid", contentType, nil),
+		s3.WithCompression("brotli"),
+	)
+	require.ErrorIs(t, err, s3.ErrInvalidCompression)
+
+	// metadata which was not written by the client does not decompress the content
+	for _, metaData := range []map[string]string{{"Compression": "gzip"}, {"Client-Compression": "brotli"}} {
+		upload := s3.NewUpload(strings.NewReader("plain"), nil, folder+"/uncompressed", contentType, metaData)
+
+		_, err = s3Client.UploadFile(ctx, upload)
+		require.NoError(t, err)
+
+		file, err = s3Client.GetFile(ctx, upload.Path)
+		require.NoError(t, err)
+
+		fileContent, err = file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "plain", string(fileContent))
+		require.Equal(t, metaData, file.Info().MetaData)
+		require.Equal(t, s3.CompressionNone, file.Info().Compression)
+	}
+}
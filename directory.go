@@ -0,0 +1,247 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"iter"
+	"mime"
+	"net/http"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// sniffLength is the number of bytes used to detect the content type of files with an unknown extension.
+const sniffLength = 512
+
+type uploadFileFunc func(ctx context.Context, upload *Upload, options ...UploadOption) (*UploadInfo, error)
+
+// uploadDirectory uploads the files of the local folder under the given prefix, keeping the folder structure.
+// The results are sorted by path, files uploaded successfully are returned along with a BulkError.
+func uploadDirectory(
+	ctx context.Context,
+	localPath, prefix string,
+	clientConcurrency int,
+	uploadFile uploadFileFunc,
+	options ...UploadDirectoryOption,
+) ([]*UploadedFile, error) {
+	opts := new(uploadDirectoryOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := opts.validatePatterns(); err != nil {
+		return nil, err
+	}
+
+	keyPrefix := ""
+
+	if prefix != "" {
+		keyPrefix = folderPrefix(prefix)
+	}
+
+	listKeys := func(context.Context) iter.Seq2[string, error] {
+		return func(yield func(string, error) bool) {
+			for relPath, err := range opts.localFiles(localPath) {
+				if !yield(keyPrefix+relPath, err) {
+					return
+				}
+			}
+		}
+	}
+
+	results, err := runBulk(
+		ctx,
+		listKeys,
+		opts.concurrencyLimit(clientConcurrency),
+		opts.failFast,
+		func(ctx context.Context, key string) (*UploadedFile, error) {
+			localFile := filepath.Join(localPath, filepath.FromSlash(strings.TrimPrefix(key, keyPrefix)))
+
+			return uploadLocalFile(ctx, localFile, key, uploadFile, opts.uploadOptions)
+		},
+	)
+
+	return collectResults(results, err, OperationUploadFile, true)
+}
+
+func uploadLocalFile(
+	ctx context.Context,
+	localFile, key string,
+	uploadFile uploadFileFunc,
+	options []UploadOption,
+) (*UploadedFile, error) {
+	content, err := os.Open(localFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer content.Close()
+
+	stat, err := content.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := stat.Size()
+
+	contentType, err := detectContentType(content, localFile)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := uploadFile(ctx, NewUpload(content, &size, key, contentType, nil), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded := &UploadedFile{
+		LocalPath:  localFile,
+		Path:       key,
+		UploadInfo: *info,
+	}
+
+	return uploaded, nil
+}
+
+// detectContentType returns the content type registered for the file extension.
+// For unknown extensions, the content type is detected from the beginning of the content.
+func detectContentType(content io.ReadSeeker, fileName string) (string, error) {
+	if contentType := mime.TypeByExtension(filepath.Ext(fileName)); contentType != "" {
+		return contentType, nil
+	}
+
+	buf := make([]byte, sniffLength)
+
+	n, err := io.ReadFull(content, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", err
+	}
+
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return http.DetectContentType(buf[:n]), nil
+}
+
+// localFilter selects the local files of a folder.
+type localFilter struct {
+	include  []string
+	exclude  []string
+	symlinks SymlinkPolicy
+}
+
+func (o *localFilter) validatePatterns() error {
+	const errMessage = "invalid pattern '%s': %w"
+
+	for _, pattern := range slices.Concat(o.include, o.exclude) {
+		if _, err := pathpkg.Match(pattern, ""); err != nil {
+			return fmt.Errorf(errMessage, pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// localFiles returns the slash separated paths of the files to upload, relative to the given folder.
+func (o *localFilter) localFiles(root string) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		// the real paths of the folders being walked, to skip links pointing to a parent folder
+		parents := make(map[string]bool)
+
+		var walk func(folder, relFolder string) bool
+
+		walk = func(folder, relFolder string) bool {
+			if o.symlinks == SymlinkFollow {
+				realPath, err := filepath.EvalSymlinks(folder)
+				if err != nil {
+					return yield("", err)
+				}
+
+				if parents[realPath] {
+					return true
+				}
+
+				parents[realPath] = true
+				defer delete(parents, realPath)
+			}
+
+			entries, err := os.ReadDir(folder)
+			if err != nil {
+				return yield("", err)
+			}
+
+			for _, entry := range entries {
+				entryPath := filepath.Join(folder, entry.Name())
+				relPath := pathpkg.Join(relFolder, entry.Name())
+				mode := entry.Type()
+
+				if mode&fs.ModeSymlink != 0 {
+					if o.symlinks != SymlinkFollow {
+						continue
+					}
+
+					info, err := os.Stat(entryPath)
+					if err != nil {
+						return yield("", err)
+					}
+
+					mode = info.Mode().Type()
+				}
+
+				if !mode.IsDir() && !mode.IsRegular() {
+					continue // devices, sockets and pipes
+				}
+
+				if matchesAny(o.exclude, relPath) {
+					continue
+				}
+
+				if mode.IsDir() {
+					if !walk(entryPath, relPath) {
+						return false
+					}
+
+					continue
+				}
+
+				if len(o.include) > 0 && !matchesAny(o.include, relPath) {
+					continue
+				}
+
+				if !yield(relPath, nil) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		walk(root, "")
+	}
+}
+
+// matchesAny reports whether the relative path matches any of the patterns.
+// Patterns without a slash are also matched against the name.
+func matchesAny(patterns []string, relPath string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := pathpkg.Match(pattern, relPath); matched {
+			return true
+		}
+
+		if !strings.Contains(pattern, "/") {
+			if matched, _ := pathpkg.Match(pattern, pathpkg.Base(relPath)); matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"cmp"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+)
+
+const (
+	customerKeySize     = 32
+	minMasterKeySize    = 32
+	tenantKeyInfoPrefix = "s3-client sse-c tenant "
+	sseAlgorithmAES256  = "AES256"
+	sseAlgorithmKMS     = "aws:kms"
+	sseAlgorithmKMSDSSE = "aws:kms:dsse"
+)
+
+// EncryptionType is the server-side encryption method of a file.
+type EncryptionType string
+
+const (
+	// EncryptionNone means the file is not encrypted by s3, apart from a default encryption of the bucket
+	// s3 does not report.
+	EncryptionNone EncryptionType = ""
+	// EncryptionS3 encrypts the file with keys managed by s3 (SSE-S3).
+	EncryptionS3 EncryptionType = "SSE-S3"
+	// EncryptionKMS encrypts the file with a key of the key management service (SSE-KMS).
+	EncryptionKMS EncryptionType = "SSE-KMS"
+	// EncryptionCustomerKey encrypts the file with a key provided by the client (SSE-C).
+	// s3 does not store the key, so it has to be provided to read the file again.
+	EncryptionCustomerKey EncryptionType = "SSE-C"
+)
+
+// Encryption is the server-side encryption of files. It is created by SSES3, SSEKMS, SSEC or TenantSSEC.
+type Encryption struct {
+	sse encrypt.ServerSide
+}
+
+// NoEncryption disables the default encryption of the client for a single call.
+func NoEncryption() *Encryption {
+	return &Encryption{}
+}
+
+// SSES3 encrypts files with keys managed by s3.
+func SSES3() *Encryption {
+	return &Encryption{sse: encrypt.NewSSE()}
+}
+
+// SSEKMS encrypts files with the given key of the key management service.
+// An empty key id uses the default key of the bucket, the context is optional.
+func SSEKMS(keyID string, context map[string]string) *Encryption {
+	var kmsContext any
+
+	if len(context) > 0 {
+		kmsContext = context
+	}
+
+	sse, _ := encrypt.NewSSEKMS(keyID, kmsContext) //nolint:errcheck // string maps always marshal
+
+	return &Encryption{sse: sse}
+}
+
+// SSEC encrypts files with the given 256 bit key. s3 does not store the key,
+// so every request reading the file requires the same key.
+func SSEC(key []byte) (*Encryption, error) {
+	const errMessage = "failed to create SSE-C encryption: %w"
+
+	if len(key) != customerKeySize {
+		return nil, fmt.Errorf(errMessage, ErrInvalidEncryptionKey)
+	}
+
+	sse, err := encrypt.NewSSEC(key)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return &Encryption{sse: sse}, nil
+}
+
+// TenantSSEC encrypts files with a key derived from the master key for the given tenant, using HKDF-SHA256.
+// Every tenant gets its own key, while only the master key of at least 256 bit has to be kept.
+func TenantSSEC(masterKey []byte, tenant string) (*Encryption, error) {
+	const errMessage = "failed to derive SSE-C encryption: %w"
+
+	if len(masterKey) < minMasterKeySize || tenant == "" {
+		return nil, fmt.Errorf(errMessage, ErrInvalidEncryptionKey)
+	}
+
+	key, err := hkdf.Key(sha256.New, masterKey, nil, tenantKeyInfoPrefix+tenant, customerKeySize)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return SSEC(key)
+}
+
+// Type returns the encryption method.
+func (e *Encryption) Type() EncryptionType {
+	if e == nil || e.sse == nil {
+		return EncryptionNone
+	}
+
+	switch e.sse.Type() {
+	case encrypt.S3:
+		return EncryptionS3
+	case encrypt.KMS:
+		return EncryptionKMS
+	case encrypt.SSEC:
+		return EncryptionCustomerKey
+	default:
+		return EncryptionNone
+	}
+}
+
+// serverSide returns the encryption of the request, the override takes precedence over the default of the client.
+func (c *client) serverSide(override *Encryption) encrypt.ServerSide {
+	return cmp.Or(override, c.encryption, NoEncryption()).sse
+}
+
+// customerKey returns the encryption if it requires the key to be sent when reading the file, i.e. for SSE-C.
+func customerKey(sse encrypt.ServerSide) encrypt.ServerSide {
+	if sse == nil || sse.Type() != encrypt.SSEC {
+		return nil
+	}
+
+	return sse
+}
+
+// objectEncryption returns the encryption method from the response headers of the object.
+func objectEncryption(headers http.Header) EncryptionType {
+	if headers.Get(encrypt.SseCustomerAlgorithm) != "" {
+		return EncryptionCustomerKey
+	}
+
+	switch headers.Get(encrypt.SseGenericHeader) {
+	case sseAlgorithmAES256:
+		return EncryptionS3
+	case sseAlgorithmKMS, sseAlgorithmKMSDSSE:
+		return EncryptionKMS
+	default:
+		return EncryptionNone
+	}
+}
@@ -0,0 +1,174 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Encryption(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("SSE-S3", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		filePath := "test-encryption/" + uuid.NewString()
+
+		_, err := s3Client.UploadFile(ctx,
+			s3.NewUpload(strings.NewReader("encrypted"), nil, filePath, contentType, nil),
+			s3.WithEncryption(s3.SSES3()),
+		)
+		require.NoError(t, err)
+
+		info, err := s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, s3.EncryptionS3, info.Encryption)
+
+		file, err := s3Client.GetFile(ctx, filePath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		content, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "encrypted", string(content))
+		require.Equal(t, s3.EncryptionS3, file.Info().Encryption)
+	})
+
+	t.Run("SSE-KMS", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		filePath := "test-encryption/" + uuid.NewString()
+
+		_, err := s3Client.UploadFile(ctx,
+			s3.NewUpload(strings.NewReader("encrypted"), nil, filePath, contentType, nil),
+			s3.WithEncryption(s3.SSEKMS(testKMSKeyID, map[string]string{"tenant": "tenant-a"})),
+		)
+		require.NoError(t, err)
+
+		info, err := s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, s3.EncryptionKMS, info.Encryption)
+	})
+
+	t.Run("default encryption", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t, s3.WithDefaultEncryption(s3.SSES3()))
+
+		folder := "test-encryption/" + uuid.NewString()
+
+		_, err := s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader("encrypted"), nil, folder+"/default", contentType, nil))
+		require.NoError(t, err)
+
+		_, err = s3Client.UploadFile(ctx,
+			s3.NewUpload(strings.NewReader("plain"), nil, folder+"/plain", contentType, nil),
+			s3.WithEncryption(s3.NoEncryption()),
+		)
+		require.NoError(t, err)
+
+		_, err = s3Client.CopyFile(ctx, folder+"/plain", folder+"/copied")
+		require.NoError(t, err)
+
+		_, err = s3Client.CopyFile(ctx, folder+"/default", folder+"/copied-plain", s3.WithCopyEncryption(s3.NoEncryption()))
+		require.NoError(t, err)
+
+		for fileName, expected := range map[string]s3.EncryptionType{
+			"default":      s3.EncryptionS3,
+			"plain":        s3.EncryptionNone,
+			"copied":       s3.EncryptionS3,
+			"copied-plain": s3.EncryptionNone,
+		} {
+			info, err := s3Client.GetFileInfo(ctx, folder+"/"+fileName)
+			require.NoError(t, err)
+			require.Equal(t, expected, info.Encryption, fileName)
+		}
+	})
+
+	t.Run("SSE-C upload links", func(t *testing.T) {
+		t.Parallel()
+
+		masterKey := bytes.Repeat([]byte{0x42}, 32)
+
+		tenantA, err := s3.TenantSSEC(masterKey, "tenant-a")
+		require.NoError(t, err)
+
+		tenantB, err := s3.TenantSSEC(masterKey, "tenant-b")
+		require.NoError(t, err)
+
+		s3Client := getS3Client(t, s3.WithDefaultEncryption(tenantA))
+
+		keyMD5 := func(options ...s3.UploadLinkOption) string {
+			link, err := s3Client.CreateUploadLink(ctx, "test-encryption/"+uuid.NewString(), time.Hour, options...)
+			require.NoError(t, err)
+			require.Equal(t, "AES256", link.Headers.Get(encrypt.SseCustomerAlgorithm))
+
+			return link.Headers.Get(encrypt.SseCustomerKeyMD5)
+		}
+
+		// keys are derived deterministically, so every tenant always gets the same key
+		require.NotEmpty(t, keyMD5())
+		require.Equal(t, keyMD5(), keyMD5(s3.WithUploadLinkEncryption(tenantA)))
+		require.NotEqual(t, keyMD5(), keyMD5(s3.WithUploadLinkEncryption(tenantB)))
+
+		link, err := s3Client.CreateUploadLink(ctx, "test-encryption/plain", time.Hour, s3.WithUploadLinkEncryption(s3.NoEncryption()))
+		require.NoError(t, err)
+		require.Empty(t, link.Headers.Get(encrypt.SseCustomerAlgorithm))
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.SSEC(make([]byte, 16))
+		require.ErrorIs(t, err, s3.ErrInvalidEncryptionKey)
+
+		_, err = s3.TenantSSEC(make([]byte, 16), "tenant-a")
+		require.ErrorIs(t, err, s3.ErrInvalidEncryptionKey)
+
+		_, err = s3.TenantSSEC(make([]byte, 32), "")
+		require.ErrorIs(t, err, s3.ErrInvalidEncryptionKey)
+
+		encryption, err := s3.SSEC(make([]byte, 32))
+		require.NoError(t, err)
+		require.Equal(t, s3.EncryptionCustomerKey, encryption.Type())
+		require.Equal(t, s3.EncryptionNone, s3.NoEncryption().Type())
+	})
+}
+
+func testStoreEncryption(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := s3Client.UploadFile(ctx,
+		s3.NewUpload(strings.NewReader("encrypted"), nil, "test-store-encryption/"+uuid.NewString(), contentType, nil),
+		s3.WithEncryption(s3.SSES3()),
+	)
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	uploaded := uploadTestFileWithClient(t, s3Client, "test-store-encryption", testFile1Name)
+
+	info, err := s3Client.GetFileInfo(ctx, uploaded.filePath, s3.WithFileInfoEncryption(s3.NoEncryption()))
+	require.NoError(t, err)
+	require.Equal(t, s3.EncryptionNone, info.Encryption)
+
+	encryption, err := s3.TenantSSEC(bytes.Repeat([]byte{0x42}, 32), "tenant-a")
+	require.NoError(t, err)
+
+	_, err = s3Client.GetFile(ctx, uploaded.filePath, s3.WithGetEncryption(encryption))
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	_, err = s3Client.CopyFile(ctx, uploaded.filePath, uploaded.filePath+"-copy", s3.WithCopyEncryption(s3.SSES3()))
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+}
@@ -0,0 +1,449 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"bufio"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	envelopeAlgorithm    = "AES-256-GCM"
+	envelopeChunkSize    = 64 << 10
+	envelopeMaxChunkSize = 16 << 20
+	dataKeySize          = 32
+	gcmNonceSize         = 12
+	aesGCMOverhead       = 16
+
+	keyEnvelopeAlgorithm = "Client-Encryption-Algorithm"
+	keyEnvelopeChunkSize = "Client-Encryption-Chunk-Size"
+	keyEnvelopeKeyID     = "Client-Encryption-Key-Id"
+	keyEnvelopeKey       = "Client-Encryption-Key"
+)
+
+var envelopeMetaDataKeys = []string{keyEnvelopeAlgorithm, keyEnvelopeChunkSize, keyEnvelopeKeyID, keyEnvelopeKey}
+
+// KeyProvider wraps and unwraps the data keys of client-side encrypted files, e.g. using a key management service.
+// Every file is encrypted with its own random data key, which is stored wrapped in the metadata of the file.
+type KeyProvider interface {
+	// WrapKey encrypts the data key with the current key and returns the id of that key along with the wrapped key.
+	WrapKey(ctx context.Context, dataKey []byte) (keyID string, wrappedKey []byte, err error)
+	// UnwrapKey decrypts the data key wrapped by the key with the given id.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// StaticKeyProvider wraps data keys with local 256 bit keys using AES-256-GCM.
+type StaticKeyProvider struct {
+	currentKeyID string
+	keys         map[string]cipher.AEAD
+}
+
+// NewStaticKeyProvider returns a key provider wrapping new data keys with the key of the current key id.
+// All other keys are only used to unwrap the data keys of existing files, so keys are rotated by adding
+// a new key, making it the current key and re-wrapping the data keys of the files using RotateFileKey.
+func NewStaticKeyProvider(currentKeyID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	const errMessage = "failed to create static key provider: %w"
+
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf(errMessage, fmt.Errorf("%w: unknown current key '%s'", ErrInvalidEncryptionKey, currentKeyID))
+	}
+
+	provider := &StaticKeyProvider{
+		currentKeyID: currentKeyID,
+		keys:         make(map[string]cipher.AEAD, len(keys)),
+	}
+
+	for keyID, key := range keys {
+		if len(key) != dataKeySize {
+			return nil, fmt.Errorf(errMessage, fmt.Errorf("%w: key '%s'", ErrInvalidEncryptionKey, keyID))
+		}
+
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf(errMessage, err)
+		}
+
+		provider.keys[keyID] = aead
+	}
+
+	return provider, nil
+}
+
+// WrapKey implements the KeyProvider interface. The wrapped key is prefixed with its random nonce.
+func (p *StaticKeyProvider) WrapKey(_ context.Context, dataKey []byte) (string, []byte, error) {
+	nonce := make([]byte, gcmNonceSize)
+
+	if _, err := rand.Read(nonce); err != nil {
+		return "", nil, err
+	}
+
+	return p.currentKeyID, p.keys[p.currentKeyID].Seal(nonce, nonce, dataKey, []byte(p.currentKeyID)), nil
+}
+
+// UnwrapKey implements the KeyProvider interface.
+func (p *StaticKeyProvider) UnwrapKey(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	aead, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key '%s'", ErrDecryptionFailed, keyID)
+	}
+
+	if len(wrappedKey) < gcmNonceSize {
+		return nil, ErrDecryptionFailed
+	}
+
+	dataKey, err := aead.Open(nil, wrappedKey[:gcmNonceSize], wrappedKey[gcmNonceSize:], []byte(keyID))
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+
+	return dataKey, nil
+}
+
+// envelope describes the client-side encryption of a file, as stored in its metadata.
+type envelope struct {
+	keyID      string
+	wrappedKey []byte
+	chunkSize  int
+}
+
+// sealEnvelope creates a new data key wrapped by the key provider and returns the encrypting reader of the content
+// along with the metadata describing the encryption. The content is bound to the path of the file.
+func sealEnvelope(ctx context.Context, provider KeyProvider, path string, content io.Reader) (io.Reader, map[string]string, error) {
+	dataKey := make([]byte, dataKeySize)
+
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, nil, err
+	}
+
+	keyID, wrappedKey, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env := &envelope{keyID: keyID, wrappedKey: wrappedKey, chunkSize: envelopeChunkSize}
+
+	return newEncryptingReader(content, aead, env.chunkSize, path), env.metaData(), nil
+}
+
+// parseEnvelope returns the client-side encryption of a file from its metadata, or nil if the file is not encrypted.
+func parseEnvelope(metaData map[string]string) (*envelope, error) {
+	algorithm, ok := metaData[keyEnvelopeAlgorithm]
+	if !ok {
+		return nil, nil //nolint:nilnil // files which are not encrypted
+	}
+
+	if algorithm != envelopeAlgorithm {
+		return nil, fmt.Errorf("%w: unknown algorithm '%s'", ErrDecryptionFailed, algorithm)
+	}
+
+	chunkSize, err := strconv.Atoi(metaData[keyEnvelopeChunkSize])
+	if err != nil || chunkSize < 1 || chunkSize > envelopeMaxChunkSize {
+		return nil, fmt.Errorf("%w: invalid chunk size", ErrDecryptionFailed)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(metaData[keyEnvelopeKey])
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid wrapped key", ErrDecryptionFailed)
+	}
+
+	env := &envelope{
+		keyID:      metaData[keyEnvelopeKeyID],
+		wrappedKey: wrappedKey,
+		chunkSize:  chunkSize,
+	}
+
+	return env, nil
+}
+
+func (e *envelope) metaData() map[string]string {
+	return map[string]string{
+		keyEnvelopeAlgorithm: envelopeAlgorithm,
+		keyEnvelopeChunkSize: strconv.Itoa(e.chunkSize),
+		keyEnvelopeKeyID:     e.keyID,
+		keyEnvelopeKey:       base64.StdEncoding.EncodeToString(e.wrappedKey),
+	}
+}
+
+// open unwraps the data key and returns the decrypting reader of the content encrypted for the given path.
+func (e *envelope) open(ctx context.Context, provider KeyProvider, path string, content io.ReadCloser) (io.ReadCloser, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("%w: no key provider", ErrDecryptionFailed)
+	}
+
+	dataKey, err := provider.UnwrapKey(ctx, e.keyID, e.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+
+	return newDecryptingReader(content, aead, e.chunkSize, path), nil
+}
+
+// reseal returns the content encrypted for another path along with the metadata describing its encryption.
+// The content is bound to its path, so copies to another path are decrypted and encrypted with a new data key.
+func (e *envelope) reseal(
+	ctx context.Context,
+	provider KeyProvider,
+	srcPath, dstPath string,
+	content io.ReadCloser,
+) (io.Reader, map[string]string, error) {
+	plain, err := e.open(ctx, provider, srcPath, content)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return sealEnvelope(ctx, provider, dstPath, plain)
+}
+
+// takeEnvelope returns the client-side encryption of the file and removes its keys from the metadata of the file info.
+// The size of the file info is set to the size of the content before it was encrypted.
+func takeEnvelope(info *FileInfo) (*envelope, error) {
+	env, err := parseEnvelope(info.MetaData)
+	if err != nil || env == nil {
+		return nil, err
+	}
+
+	for _, key := range envelopeMetaDataKeys {
+		delete(info.MetaData, key)
+	}
+
+	info.Size = plaintextSize(info.Size, env.chunkSize)
+	info.EncryptionKeyID = env.keyID
+
+	return env, nil
+}
+
+// rewrapKey returns the envelope with the data key wrapped by the current key of the key provider,
+// or nil if the data key is already wrapped by the current key.
+func rewrapKey(ctx context.Context, provider KeyProvider, env *envelope) (*envelope, error) {
+	if provider == nil {
+		return nil, fmt.Errorf("%w: no key provider", ErrNotSupported)
+	}
+
+	if env == nil {
+		return nil, ErrNotEncrypted
+	}
+
+	dataKey, err := provider.UnwrapKey(ctx, env.keyID, env.wrappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID, wrappedKey, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if keyID == env.keyID {
+		return nil, nil //nolint:nilnil // the data key is already wrapped by the current key
+	}
+
+	return &envelope{keyID: keyID, wrappedKey: wrappedKey, chunkSize: env.chunkSize}, nil
+}
+
+// encryptedSize returns the size of the encrypted content, a negative size stays unknown.
+func encryptedSize(size int64) int64 {
+	if size < 0 {
+		return size
+	}
+
+	chunks := max((size+envelopeChunkSize-1)/envelopeChunkSize, 1)
+
+	return size + chunks*aesGCMOverhead
+}
+
+// plaintextSize returns the size of the content encrypted in chunks of the given size.
+func plaintextSize(size int64, chunkSize int) int64 {
+	sealedChunkSize := int64(chunkSize + aesGCMOverhead)
+	chunks := (size + sealedChunkSize - 1) / sealedChunkSize
+
+	return size - chunks*aesGCMOverhead
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce returns the nonce of the chunk with the given index. Every data key encrypts a single file,
+// so the index is unique. The last chunk is flagged, so truncated content is detected.
+func chunkNonce(index uint64, last bool) []byte {
+	nonce := make([]byte, gcmNonceSize)
+
+	binary.BigEndian.PutUint64(nonce[3:11], index)
+
+	if last {
+		nonce[11] = 1
+	}
+
+	return nonce
+}
+
+// chunkAAD returns the additional data authenticated with the chunk with the given index. It binds the chunk
+// to the path of the file and to its position, so neither the content of another file nor reordered chunks
+// are accepted.
+func chunkAAD(path string, index uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(path), index)
+}
+
+// encryptingReader encrypts the content in chunks of equal size while it is read.
+type encryptingReader struct {
+	source  *bufio.Reader
+	aead    cipher.AEAD
+	path    string
+	plain   []byte
+	sealed  []byte
+	pending []byte
+	index   uint64
+	done    bool
+}
+
+func newEncryptingReader(content io.Reader, aead cipher.AEAD, chunkSize int, path string) *encryptingReader {
+	return &encryptingReader{
+		source: bufio.NewReaderSize(content, chunkSize),
+		aead:   aead,
+		path:   path,
+		plain:  make([]byte, chunkSize),
+		sealed: make([]byte, 0, chunkSize+aead.Overhead()),
+	}
+}
+
+// Read implements the io.Reader interface.
+func (r *encryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		if err := r.sealChunk(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+func (r *encryptingReader) sealChunk() error {
+	n, err := io.ReadFull(r.source, r.plain)
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		r.done = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.source.Peek(1); errors.Is(err, io.EOF) {
+			r.done = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	r.pending = r.aead.Seal(r.sealed[:0], chunkNonce(r.index, r.done), r.plain[:n], chunkAAD(r.path, r.index))
+	r.index++
+
+	return nil
+}
+
+// decryptingReader decrypts and authenticates the content chunk by chunk while it is read.
+type decryptingReader struct {
+	source  *bufio.Reader
+	closer  io.Closer
+	aead    cipher.AEAD
+	path    string
+	sealed  []byte
+	plain   []byte
+	pending []byte
+	index   uint64
+	done    bool
+	err     error
+}
+
+func newDecryptingReader(content io.ReadCloser, aead cipher.AEAD, chunkSize int, path string) *decryptingReader {
+	return &decryptingReader{
+		source: bufio.NewReaderSize(content, chunkSize+aead.Overhead()),
+		closer: content,
+		aead:   aead,
+		path:   path,
+		sealed: make([]byte, chunkSize+aead.Overhead()),
+		plain:  make([]byte, 0, chunkSize),
+	}
+}
+
+// Read implements the io.Reader interface.
+func (r *decryptingReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		if r.done {
+			return 0, io.EOF
+		}
+
+		r.err = r.openChunk()
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+
+	return n, nil
+}
+
+// Close implements the io.Closer interface.
+func (r *decryptingReader) Close() error {
+	return r.closer.Close()
+}
+
+func (r *decryptingReader) openChunk() error {
+	n, err := io.ReadFull(r.source, r.sealed)
+
+	last := false
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return err
+	default:
+		if _, err := r.source.Peek(1); errors.Is(err, io.EOF) {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(r.index, last), r.sealed[:n], chunkAAD(r.path, r.index))
+	if err != nil {
+		return ErrDecryptionFailed
+	}
+
+	r.pending = plain
+	r.done = last
+	r.index++
+
+	return nil
+}
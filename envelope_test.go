@@ -0,0 +1,276 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ClientSideEncryption(t *testing.T) {
+	t.Parallel()
+
+	t.Run("s3", func(t *testing.T) {
+		t.Parallel()
+
+		testClientSideEncryption(t, getS3Client, "test-client-side-encryption")
+	})
+
+	t.Run("local", func(t *testing.T) {
+		t.Parallel()
+
+		rootPath := t.TempDir()
+
+		// all clients have to share the files, so they use the same root path
+		newClient := func(t *testing.T, options ...s3.ClientOption) s3.Client {
+			t.Helper()
+
+			s3Client, err := s3.NewLocalClient(rootPath, options...)
+			require.NoError(t, err)
+
+			return s3Client
+		}
+
+		testClientSideEncryption(t, newClient, "test-client-side-encryption")
+	})
+
+	t.Run("corrupted content", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		rootPath := t.TempDir()
+
+		provider, err := s3.NewStaticKeyProvider("current", map[string][]byte{"current": bytes.Repeat([]byte{0x01}, 32)})
+		require.NoError(t, err)
+
+		s3Client, err := s3.NewLocalClient(rootPath, s3.WithClientSideEncryption(provider))
+		require.NoError(t, err)
+
+		content := bytes.Repeat([]byte("confidential"), 10000)
+		filePath := "test-client-side-encryption/corrupted"
+
+		_, err = s3Client.UploadFile(ctx, s3.NewUpload(bytes.NewReader(content), nil, filePath, contentType, nil))
+		require.NoError(t, err)
+
+		localPath := filepath.Join(rootPath, filepath.FromSlash(filePath))
+
+		stored, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		require.NotContains(t, string(stored), "confidential")
+
+		// flipping a single bit fails the authentication of its chunk
+		stored[len(stored)/2] ^= 0x01
+
+		err = os.WriteFile(localPath, stored, 0o600)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(ctx, filePath)
+		require.NoError(t, err)
+
+		_, err = file.Bytes()
+		require.ErrorIs(t, err, s3.ErrDecryptionFailed)
+
+		// dropping the final chunk is detected, although all remaining chunks are authentic
+		stored[len(stored)/2] ^= 0x01
+
+		err = os.WriteFile(localPath, stored[:64<<10+16], 0o600)
+		require.NoError(t, err)
+
+		file, err = s3Client.GetFile(ctx, filePath)
+		require.NoError(t, err)
+
+		_, err = file.Bytes()
+		require.ErrorIs(t, err, s3.ErrDecryptionFailed)
+	})
+
+	t.Run("swapped content", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		rootPath := t.TempDir()
+
+		provider, err := s3.NewStaticKeyProvider("current", map[string][]byte{"current": bytes.Repeat([]byte{0x01}, 32)})
+		require.NoError(t, err)
+
+		s3Client, err := s3.NewLocalClient(rootPath, s3.WithClientSideEncryption(provider))
+		require.NoError(t, err)
+
+		for _, filePath := range []string{"test-client-side-encryption/original", "test-client-side-encryption/other"} {
+			_, err = s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader(filePath), nil, filePath, contentType, nil))
+			require.NoError(t, err)
+		}
+
+		// moved files are encrypted for their new path
+		_, err = s3Client.MoveFile(ctx, "test-client-side-encryption/other", "test-client-side-encryption/moved")
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(ctx, "test-client-side-encryption/moved")
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "test-client-side-encryption/other", string(fileContent))
+
+		// the content is bound to its path, so it is rejected along with its wrapped key under another path
+		for _, folder := range []string{"", filepath.Join(".s3-client", "objects")} {
+			original := filepath.Join(rootPath, folder, "test-client-side-encryption", "original")
+			moved := filepath.Join(rootPath, folder, "test-client-side-encryption", "moved")
+
+			stored, err := os.ReadFile(original)
+			require.NoError(t, err)
+
+			err = os.WriteFile(moved, stored, 0o600)
+			require.NoError(t, err)
+		}
+
+		file, err = s3Client.GetFile(ctx, "test-client-side-encryption/moved")
+		require.NoError(t, err)
+
+		_, err = file.Bytes()
+		require.ErrorIs(t, err, s3.ErrDecryptionFailed)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewStaticKeyProvider("current", map[string][]byte{"current": make([]byte, 16)})
+		require.ErrorIs(t, err, s3.ErrInvalidEncryptionKey)
+
+		_, err = s3.NewStaticKeyProvider("missing", map[string][]byte{"current": make([]byte, 32)})
+		require.ErrorIs(t, err, s3.ErrInvalidEncryptionKey)
+	})
+}
+
+// testClientSideEncryption tests the encryption using multiple clients, so they have to share their files.
+func testClientSideEncryption(
+	t *testing.T,
+	newClient func(t *testing.T, options ...s3.ClientOption) s3.Client,
+	testFolder string,
+) {
+	t.Helper()
+
+	ctx := context.Background()
+	folder := testFolder + "/" + uuid.NewString()
+
+	oldKey := bytes.Repeat([]byte{0x01}, 32)
+	newKey := bytes.Repeat([]byte{0x02}, 32)
+
+	oldProvider, err := s3.NewStaticKeyProvider("old", map[string][]byte{"old": oldKey})
+	require.NoError(t, err)
+
+	newProvider, err := s3.NewStaticKeyProvider("new", map[string][]byte{"old": oldKey, "new": newKey})
+	require.NoError(t, err)
+
+	s3Client := newClient(t, s3.WithClientSideEncryption(oldProvider), s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true))
+	plainClient := newClient(t)
+
+	contents := map[string][]byte{
+		"empty":       {},
+		"small":       []byte("confidential"),
+		"chunk":       bytes.Repeat([]byte{0x42}, 64<<10),
+		"multi-chunk": bytes.Repeat([]byte("confidential"), 20000),
+	}
+
+	for fileName, content := range contents {
+		size := int64(len(content))
+
+		upload := s3.NewUpload(bytes.NewReader(content), &size, folder+"/"+fileName, contentType, map[string]string{"Owner": "tenant-a"})
+
+		uploaded, err := s3Client.UploadFile(ctx, upload)
+		require.NoError(t, err)
+		require.Equal(t, size, uploaded.Size, fileName)
+
+		expectedChecksum, err := s3.GenerateCheckSum(s3.ChecksumAlgorithmSHA256, bytes.NewReader(content))
+		require.NoError(t, err)
+		require.Equal(t, expectedChecksum, uploaded.Checksum(s3.ChecksumAlgorithmSHA256), fileName)
+
+		file, err := s3Client.GetFile(ctx, upload.Path, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, content, fileContent, fileName)
+		require.Equal(t, size, file.Info().Size, fileName)
+		require.Equal(t, map[string]string{"Owner": "tenant-a"}, file.Info().MetaData, fileName)
+		require.Equal(t, "old", file.Info().EncryptionKeyID, fileName)
+
+		info, err := s3Client.GetFileInfo(ctx, upload.Path)
+		require.NoError(t, err)
+		require.Equal(t, size, info.Size, fileName)
+		require.Equal(t, expectedChecksum, info.Checksum(s3.ChecksumAlgorithmSHA256), fileName)
+
+		localPath := filepath.Join(t.TempDir(), fileName)
+
+		err = s3Client.DownloadFile(ctx, upload.Path, localPath)
+		require.NoError(t, err)
+
+		downloaded, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		require.Equal(t, content, downloaded, fileName)
+	}
+
+	filePath := folder + "/small"
+
+	// files which are not encrypted are read as they are
+	_, err = plainClient.UploadFile(ctx, s3.NewUpload(strings.NewReader("plain"), nil, folder+"/plain", contentType, nil))
+	require.NoError(t, err)
+
+	file, err := s3Client.GetFile(ctx, folder+"/plain")
+	require.NoError(t, err)
+
+	fileContent, err := file.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, "plain", string(fileContent))
+	require.Empty(t, file.Info().EncryptionKeyID)
+
+	err = s3Client.RotateFileKey(ctx, folder+"/plain")
+	require.ErrorIs(t, err, s3.ErrNotEncrypted)
+
+	// encrypted files cannot be read without key provider
+	_, err = plainClient.GetFile(ctx, filePath)
+	require.ErrorIs(t, err, s3.ErrDecryptionFailed)
+
+	err = plainClient.RotateFileKey(ctx, filePath)
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	copied, err := s3Client.CopyFile(ctx, filePath, folder+"/copied", s3.WithCopyMetaData(map[string]string{"Owner": "tenant-b"}))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(contents["small"])), copied.Size)
+
+	rotatingClient := newClient(t, s3.WithClientSideEncryption(newProvider))
+
+	err = rotatingClient.RotateFileKey(ctx, folder+"/copied")
+	require.NoError(t, err)
+
+	file, err = rotatingClient.GetFile(ctx, folder+"/copied", s3.WithAutoIntegrityCheck())
+	require.NoError(t, err)
+
+	fileContent, err = file.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, contents["small"], fileContent)
+	require.Equal(t, "new", file.Info().EncryptionKeyID)
+	require.Equal(t, map[string]string{"Owner": "tenant-b"}, file.Info().MetaData)
+
+	// the old key no longer unwraps the data key of the rotated file
+	_, err = s3Client.GetFile(ctx, folder+"/copied")
+	require.ErrorIs(t, err, s3.ErrDecryptionFailed)
+
+	// files already wrapped by the current key are left untouched
+	err = rotatingClient.RotateFileKey(ctx, folder+"/copied")
+	require.NoError(t, err)
+
+	for info, err := range rotatingClient.ListFiles(ctx, folder+"/", s3.WithFullFileInfo()) {
+		require.NoError(t, err)
+
+		if content, ok := contents[info.Name]; ok {
+			require.Equal(t, int64(len(content)), info.Size, info.Name)
+			require.Equal(t, "old", info.EncryptionKeyID, info.Name)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package s3 //nolint:revive // package name matches folder name
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/minio/minio-go/v7"
 )
@@ -16,13 +19,100 @@ var (
 	ErrEmptyAccessSecret = errors.New("access secret not specified")
 	// ErrEmptyBucketName occurs when the bucket name is not specified.
 	ErrEmptyBucketName = errors.New("bucket name not specified")
+	// ErrEmptyRootPath occurs when the root path of a local client is not specified.
+	ErrEmptyRootPath = errors.New("root path not specified")
+	// ErrInvalidPath occurs when the given path cannot be used as object path.
+	ErrInvalidPath = errors.New("invalid object path")
 	// ErrNotFound indicates that the requested file does not exist.
 	ErrNotFound = errors.New("file under specified filepath does not exist")
 	// ErrChecksumMismatch occurs when the checksum of the downloaded file
 	// does not match the expected checksum.
 	ErrChecksumMismatch = errors.New("checksum mismatch")
+	// ErrInvalidChecksumAlgorithm occurs when a checksum algorithm is not registered or has an invalid name.
+	ErrInvalidChecksumAlgorithm = errors.New("invalid checksum algorithm")
+	// ErrLifecycleRuleNotFound occurs when the bucket has no lifecycle rule with the given id.
+	ErrLifecycleRuleNotFound = errors.New("lifecycle rule not found")
+	// ErrInvalidLifecycleRule occurs when a lifecycle rule is incomplete or contradicting.
+	ErrInvalidLifecycleRule = errors.New("invalid lifecycle rule")
+	// ErrInvalidConcurrency occurs when the concurrency limit is less than 1.
+	ErrInvalidConcurrency = errors.New("concurrency must be at least 1")
+	// ErrInvalidContinuationToken occurs when a continuation token is malformed or was issued for another prefix.
+	ErrInvalidContinuationToken = errors.New("invalid continuation token")
+	// ErrChecksumAlgorithmExists occurs when a checksum algorithm with the same name is already registered.
+	ErrChecksumAlgorithmExists = errors.New("checksum algorithm already registered")
+	// ErrNotSupported occurs when the operation is not supported by the client.
+	ErrNotSupported = errors.New("operation not supported by this client")
+	// ErrInvalidRetention occurs when a retention has an unknown mode or no retain until date.
+	ErrInvalidRetention = errors.New("invalid retention")
+	// ErrInvalidTags occurs when tags exceed the s3 limits of 10 tags per file, 128 characters per key
+	// and 256 characters per value, or contain invalid characters.
+	ErrInvalidTags = errors.New("invalid tags")
+	// ErrInvalidEncryptionKey occurs when a SSE-C key is not 256 bit long, or a master key is shorter than 256 bit.
+	ErrInvalidEncryptionKey = errors.New("invalid encryption key")
+	// ErrDecryptionFailed occurs when a client-side encrypted file cannot be decrypted, because its data key
+	// cannot be unwrapped or the content has been modified or truncated.
+	ErrDecryptionFailed = errors.New("decryption failed")
+	// ErrNotEncrypted occurs when the key of a file which is not client-side encrypted should be rotated.
+	ErrNotEncrypted = errors.New("file is not client-side encrypted")
+	// ErrInvalidCompression occurs when a compression is unknown.
+	ErrInvalidCompression = errors.New("invalid compression")
+	// ErrInvalidRetryPolicy occurs when a retry policy allows less than 1 attempt or has negative durations.
+	ErrInvalidRetryPolicy = errors.New("invalid retry policy")
+	// ErrAccessDenied occurs when the credentials are not allowed to perform the request.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrBucketNotFound occurs when the bucket does not exist.
+	ErrBucketNotFound = errors.New("bucket not found")
+	// ErrPreconditionFailed occurs when a condition of the request is not met,
+	// e.g. the file changed while it was copied.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrEntityTooLarge occurs when a file or a part of a file exceeds the size limit of s3.
+	ErrEntityTooLarge = errors.New("entity too large")
+	// ErrThrottled occurs when s3 rejected the request because of too many requests.
+	ErrThrottled = errors.New("request throttled")
+	// ErrObjectLocked occurs when a file cannot be removed or overwritten because of its retention or legal hold.
+	ErrObjectLocked = errors.New("file is locked")
+	// ErrInvalidCredentials occurs when the access key is unknown, the secret is wrong or the token expired.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrServiceUnavailable occurs when s3 is temporarily unable to handle the request.
+	ErrServiceUnavailable = errors.New("service unavailable")
 )
 
+// errorCodes maps the s3 error codes to the errors of the client.
+var errorCodes = map[string]error{
+	"NoSuchKey":                  ErrNotFound,
+	"NoSuchVersion":              ErrNotFound,
+	"NoSuchUpload":               ErrNotFound,
+	"AccessDenied":               ErrAccessDenied,
+	"AllAccessDisabled":          ErrAccessDenied,
+	"NoSuchBucket":               ErrBucketNotFound,
+	"PreconditionFailed":         ErrPreconditionFailed,
+	"InvalidObjectName":          ErrInvalidPath,
+	"XMinioInvalidObjectName":    ErrInvalidPath,
+	"KeyTooLongError":            ErrInvalidPath,
+	"EntityTooLarge":             ErrEntityTooLarge,
+	"SlowDown":                   ErrThrottled,
+	"SlowDownRead":               ErrThrottled,
+	"SlowDownWrite":              ErrThrottled,
+	"Throttling":                 ErrThrottled,
+	"ThrottlingException":        ErrThrottled,
+	"RequestLimitExceeded":       ErrThrottled,
+	"ObjectLocked":               ErrObjectLocked,
+	"InvalidAccessKeyId":         ErrInvalidCredentials,
+	"SignatureDoesNotMatch":      ErrInvalidCredentials,
+	"ExpiredToken":               ErrInvalidCredentials,
+	"InvalidToken":               ErrInvalidCredentials,
+	"ServiceUnavailable":         ErrServiceUnavailable,
+	"XMinioServerNotInitialized": ErrServiceUnavailable,
+}
+
+// errorStatusCodes maps the http status codes of responses without known error code to the errors of the client.
+var errorStatusCodes = map[int]error{
+	http.StatusForbidden:          ErrAccessDenied,
+	http.StatusPreconditionFailed: ErrPreconditionFailed,
+	http.StatusTooManyRequests:    ErrThrottled,
+	http.StatusServiceUnavailable: ErrServiceUnavailable,
+}
+
 // BucketDoesNotExistError occurs when the given bucket does not exist.
 type BucketDoesNotExistError struct {
 	bucketName string
@@ -33,29 +123,197 @@ func (e *BucketDoesNotExistError) Error() string {
 	return fmt.Sprintf("bucket '%s' does not exist", e.bucketName)
 }
 
-// DownloadingFilesFailedError occurs when downloading files from s3 failed.
-type DownloadingFilesFailedError struct {
-	errs []error
+// Is reports whether the target is ErrBucketNotFound.
+func (e *BucketDoesNotExistError) Is(target error) bool {
+	return target == ErrBucketNotFound
+}
+
+// RequestError occurs when s3 rejected a request. Use errors.Is to check for the kind of the error,
+// e.g. ErrAccessDenied, and errors.As to get the minio.ErrorResponse.
+type RequestError struct {
+	// Err is the kind of the error, e.g. ErrAccessDenied. It is nil for errors of an unknown kind.
+	Err error
+	// Code is the s3 error code, e.g. AccessDenied.
+	Code       string
+	Message    string
+	StatusCode int
+	// RequestID identifies the request in the logs of the s3 provider.
+	RequestID string
+	// Key is the path of the file, it is empty for requests on the bucket.
+	Key      string
+	response minio.ErrorResponse
 }
 
 // Error implements the error interface.
-func (e *DownloadingFilesFailedError) Error() string {
-	return fmt.Sprintf("failed to download files from s3: %v", e.errs)
+func (e *RequestError) Error() string {
+	message := cmp.Or(e.Message, e.Code)
+
+	if e.Err != nil {
+		message = e.Err.Error() + ": " + message
+	}
+
+	details := make([]string, 0, 3)
+
+	if e.Key != "" {
+		details = append(details, fmt.Sprintf("key '%s'", e.Key))
+	}
+
+	details = append(details, fmt.Sprintf("status %d", e.StatusCode))
+
+	if e.RequestID != "" {
+		details = append(details, "request id "+e.RequestID)
+	}
+
+	return fmt.Sprintf("%s (%s)", message, strings.Join(details, ", "))
 }
 
+// Unwrap returns the kind of the error and the minio.ErrorResponse.
+func (e *RequestError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.response}
+	}
+
+	return []error{e.Err, e.response}
+}
+
+// Operation names an operation of the client, e.g. the operation which failed for a key of a bulk operation.
+type Operation string
+
+const (
+	// OperationGetFile is the operation of getting a file.
+	OperationGetFile Operation = "get file"
+	// OperationGetFileInfo is the operation of getting a file info.
+	OperationGetFileInfo Operation = "get file info"
+	// OperationDownloadFile is the operation of downloading a file.
+	OperationDownloadFile Operation = "download file"
+	// OperationRemoveFile is the operation of removing a file.
+	OperationRemoveFile Operation = "remove file"
+	// OperationUploadFile is the operation of uploading a file.
+	OperationUploadFile Operation = "upload file"
+	// OperationCopyFile is the operation of copying a file.
+	OperationCopyFile Operation = "copy file"
+	// OperationListFiles is the operation of listing files or their versions.
+	OperationListFiles Operation = "list files"
+	// OperationTagFile is the operation of getting or changing the tags of a file.
+	OperationTagFile Operation = "tag file"
+	// OperationLockFile is the operation of getting or changing the retention or legal hold of a file.
+	OperationLockFile Operation = "lock file"
+	// OperationConfigureBucket is the operation of getting or changing the configuration of the bucket,
+	// i.e. its versioning, object lock or lifecycle rules.
+	OperationConfigureBucket Operation = "configure bucket"
+)
+
+// KeyError occurs when an operation failed for a single key of a bulk operation.
+type KeyError struct {
+	Key       string
+	Operation Operation
+	Err       error
+}
+
+// Error implements the error interface.
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%s '%s': %v", e.Operation, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// BulkError occurs when an operation on multiple files failed for some of the files.
+// Use errors.Is to check for the errors of the individual keys, e.g. ErrNotFound.
+type BulkError struct {
+	// Failures contains the failed keys, sorted by key.
+	Failures []*KeyError
+	// Succeeded contains the keys processed successfully, sorted by key.
+	Succeeded []string
+}
+
+// DownloadingFilesFailedError occurs when downloading files from s3 failed.
+//
+// Deprecated: Use BulkError instead.
+type DownloadingFilesFailedError = BulkError
+
+// Error implements the error interface.
+func (e *BulkError) Error() string {
+	failures := make([]string, 0, len(e.Failures))
+
+	for _, failure := range e.Failures {
+		failures = append(failures, failure.Error())
+	}
+
+	return fmt.Sprintf("failed for %d of %d files: %s",
+		len(e.Failures), len(e.Failures)+len(e.Succeeded), strings.Join(failures, "; "))
+}
+
+// Unwrap returns the errors of the failed keys.
+func (e *BulkError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+
+	for _, failure := range e.Failures {
+		errs = append(errs, failure)
+	}
+
+	return errs
+}
+
+// FailedKeys returns the failed keys, e.g. to retry just those.
+func (e *BulkError) FailedKeys() []string {
+	keys := make([]string, 0, len(e.Failures))
+
+	for _, failure := range e.Failures {
+		keys = append(keys, failure.Key)
+	}
+
+	return keys
+}
+
+// handleClientError turns the error responses of s3 into a RequestError.
 func handleClientError(err error) error {
-	const notFound = "NoSuchKey"
+	var (
+		requestErr    *RequestError
+		minioResponse minio.ErrorResponse
+	)
 
-	var minioResponse minio.ErrorResponse
+	if errors.As(err, &requestErr) || !errors.As(err, &minioResponse) {
+		return err
+	}
 
-	if errors.As(err, &minioResponse) {
-		switch minioResponse.Code {
-		case notFound:
-			return ErrNotFound
-		default:
-			return err
-		}
+	return &RequestError{
+		Err:        classifyResponse(&minioResponse),
+		Code:       minioResponse.Code,
+		Message:    minioResponse.Message,
+		StatusCode: minioResponse.StatusCode,
+		RequestID:  minioResponse.RequestID,
+		Key:        minioResponse.Key,
+		response:   minioResponse,
 	}
+}
+
+// lockedErrorCodes are the generic error codes s3 providers reject the removal or the overwrite of locked files with,
+// instead of the ObjectLocked code. AWS uses AccessDenied and MinIO InvalidRequest, so only the message tells them apart.
+var lockedErrorCodes = map[string]bool{
+	"AccessDenied":   true,
+	"InvalidRequest": true,
+}
+
+// classifyResponse returns the kind of the error response, or nil if it is unknown.
+// The error code takes precedence over the status code.
+func classifyResponse(response *minio.ErrorResponse) error {
+	if lockedErrorCodes[response.Code] && isObjectLockedMessage(response.Message) {
+		return ErrObjectLocked
+	}
+
+	if kind, ok := errorCodes[response.Code]; ok {
+		return kind
+	}
+
+	return errorStatusCodes[response.StatusCode]
+}
+
+// isObjectLockedMessage reports whether the message rejects a request because of the lock of the file.
+func isObjectLockedMessage(message string) bool {
+	message = strings.ToLower(message)
 
-	return err
+	return strings.Contains(message, "worm") || strings.Contains(message, "object lock")
 }
@@ -0,0 +1,87 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("not found", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		filePath := "test-errors/" + uuid.NewString() + "/missing.txt"
+
+		_, err := s3Client.GetFileInfo(ctx, filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		var requestErr *s3.RequestError
+
+		require.ErrorAs(t, err, &requestErr)
+		require.Equal(t, filePath, requestErr.Key)
+		require.Equal(t, http.StatusNotFound, requestErr.StatusCode)
+		require.Equal(t, "NoSuchKey", requestErr.Code)
+		require.NotEmpty(t, requestErr.RequestID)
+
+		// the response of minio-go stays available
+		var minioResponse minio.ErrorResponse
+
+		require.ErrorAs(t, err, &minioResponse)
+		require.Equal(t, http.StatusNotFound, minioResponse.StatusCode)
+
+		_, err = s3Client.GetFile(ctx, filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		err = s3Client.PutTags(ctx, filePath, map[string]string{"team": "billing"})
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		_, err := s3Client.GetFileInfo(ctx, strings.Repeat("a", 1025))
+		require.ErrorIs(t, err, s3.ErrInvalidPath)
+	})
+
+	t.Run("bucket not found", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewClient(&s3.ClientDetails{
+			Host:         s3URL,
+			AccessKey:    s3User,
+			AccessSecret: s3Pwd,
+			BucketName:   "missing-" + uuid.NewString(),
+		})
+		require.ErrorIs(t, err, s3.ErrBucketNotFound)
+
+		var bucketErr *s3.BucketDoesNotExistError
+
+		require.ErrorAs(t, err, &bucketErr)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewClient(&s3.ClientDetails{
+			Host:         s3URL,
+			AccessKey:    s3User,
+			AccessSecret: "wrong-secret",
+			BucketName:   bucketName,
+		})
+		require.ErrorIs(t, err, s3.ErrInvalidCredentials)
+	})
+}
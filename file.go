@@ -1,13 +1,19 @@
 package s3 //nolint:revive // package name matches folder name
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"maps"
 	"time"
 )
 
 // File is a file downloaded from s3.
+//
+// Checksums which are not stored with the file are computed while the file is read and are present in
+// the file information once the file has been read completely. Requested integrity checks are performed
+// the same way, a mismatch is returned as ErrChecksumMismatch by the final Read or by Close.
 type File interface {
 	io.ReadCloser
 	// Info returns the file information.
@@ -32,16 +38,19 @@ func (f *file) Info() *FileInfo {
 func (f *file) Bytes() ([]byte, error) {
 	const errMessage = "failed to read file: %w"
 
-	defer f.Close()
+	buf := bytes.NewBuffer(make([]byte, 0, max(f.info.Size, 0)))
 
-	buf := make([]byte, f.info.Size)
+	if _, err := buf.ReadFrom(f); err != nil {
+		f.Close()
 
-	_, err := f.Read(buf)
-	if err != nil && !errors.Is(err, io.EOF) {
 		return nil, fmt.Errorf(errMessage, err)
 	}
 
-	return buf, nil
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return buf.Bytes(), nil
 }
 
 // FileInfo contains information about a file.
@@ -52,5 +61,88 @@ type FileInfo struct {
 	ContentType  string
 	MetaData     map[string]string
 	ModifiedDate time.Time
+	// VersionID is the version of the file in a versioned bucket, it is empty in unversioned buckets.
+	VersionID string
+	// Lock is the object lock state of the file, it is nil if the file is not locked.
+	Lock *LockState
+	// Encryption is the server-side encryption method of the file.
+	Encryption EncryptionType
+	// EncryptionKeyID is the id of the key wrapping the data key of client-side encrypted files,
+	// it is empty for files which are not client-side encrypted.
+	EncryptionKeyID string
+	// Compression is the compression of the stored content, the content is decompressed when it is read.
+	Compression Compression
+	// Tags are only set by listings using WithFileTags, since they require an additional request per file.
+	Tags map[string]string
 	Integrity
 }
+
+// storedContent describes how the client stored the content of a file, if it is compressed or encrypted.
+// The content is compressed before it is encrypted.
+type storedContent struct {
+	envelope   *envelope
+	compressed *compressedContent
+}
+
+// takeStoredContent returns how the content of the file is stored and removes its keys from the metadata
+// of the file info. The size of the file info is set to the size of the content read.
+func takeStoredContent(info *FileInfo) (*storedContent, error) {
+	env, err := takeEnvelope(info)
+	if err != nil {
+		return nil, err
+	}
+
+	return &storedContent{envelope: env, compressed: takeCompression(info)}, nil
+}
+
+// transformed reports whether the stored content differs from the content read,
+// so the checksums of the standard checksum headers do not apply.
+func (s *storedContent) transformed() bool {
+	return s.envelope != nil || s.compressed != nil
+}
+
+// metaData returns the metadata describing the stored content.
+func (s *storedContent) metaData() map[string]string {
+	metaData := make(map[string]string)
+
+	if s.envelope != nil {
+		maps.Copy(metaData, s.envelope.metaData())
+	}
+
+	if s.compressed != nil {
+		maps.Copy(metaData, s.compressed.metaData())
+	}
+
+	return metaData
+}
+
+// openFile decrypts and decompresses the stored content of the file. The checksums of the standard checksum
+// headers cover the stored content, so they are dropped for files which are encrypted or compressed.
+func openFile(
+	ctx context.Context,
+	provider KeyProvider,
+	content io.ReadCloser,
+	info *FileInfo,
+	native Integrity,
+) (io.ReadCloser, Integrity, error) {
+	stored, err := takeStoredContent(info)
+	if err != nil || !stored.transformed() {
+		return content, native, err
+	}
+
+	if stored.envelope != nil {
+		content, err = stored.envelope.open(ctx, provider, info.Path, content)
+		if err != nil {
+			return nil, native, err
+		}
+	}
+
+	if stored.compressed != nil {
+		content, err = newDecompressingReader(content, stored.compressed.compression)
+		if err != nil {
+			return nil, native, err
+		}
+	}
+
+	return content, Integrity{}, nil
+}
@@ -1,251 +1,555 @@
 package s3 //nolint:revive // package name matches folder name
 
 import (
-	"crypto/md5" //nolint:gosec // intended to use MD5 for hashing
+	"cmp"
+	"crypto/md5"  //nolint:gosec // intended to use MD5 for hashing
+	"crypto/sha1" //nolint:gosec // intended to use SHA-1 for hashing
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"hash"
 	"hash/crc32"
 	"io"
+	"maps"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// ChecksumAlgorithm is the name of a checksum algorithm used for integrity checks.
+type ChecksumAlgorithm string
+
+const (
+	// ChecksumAlgorithmCRC32C is the CRC32 checksum using the Castagnoli polynomial.
+	ChecksumAlgorithmCRC32C ChecksumAlgorithm = "CRC32C"
+	// ChecksumAlgorithmMD5 is the MD5 checksum.
+	ChecksumAlgorithmMD5 ChecksumAlgorithm = "MD5"
+	// ChecksumAlgorithmSHA256 is the SHA-256 checksum.
+	ChecksumAlgorithmSHA256 ChecksumAlgorithm = "SHA256"
+	// ChecksumAlgorithmSHA1 is the SHA-1 checksum.
+	ChecksumAlgorithmSHA1 ChecksumAlgorithm = "SHA1"
+	// ChecksumAlgorithmCRC32 is the CRC32 checksum using the IEEE polynomial.
+	ChecksumAlgorithmCRC32 ChecksumAlgorithm = "CRC32"
+	// ChecksumAlgorithmCRC64NVME is the CRC64 checksum using the NVMe polynomial.
+	ChecksumAlgorithmCRC64NVME ChecksumAlgorithm = "CRC64NVME"
 )
 
 const (
-	keyCR32CChecksum = "Checksum-Cr32c"
-	keyMD5Checksum   = "Checksum-Md5"
+	keyCR32CChecksum  = "Checksum-Cr32c"
+	keyMD5Checksum    = "Checksum-Md5"
+	keyChecksumPrefix = "Checksum-"
+)
+
+type checksumAlgorithm struct {
+	newHash     func() hash.Hash
+	metaDataKey string
+	nativeType  minio.ChecksumType // the standard s3 checksum header, minio.ChecksumNone if there is none
+}
+
+var (
+	checksumAlgorithmsMtx sync.RWMutex                                //nolint:gochecknoglobals // registry
+	checksumAlgorithms    = map[ChecksumAlgorithm]*checksumAlgorithm{ //nolint:gochecknoglobals // registry
+		ChecksumAlgorithmCRC32C: {
+			newHash:     func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) },
+			metaDataKey: keyCR32CChecksum,
+			nativeType:  minio.ChecksumFullObjectCRC32C,
+		},
+		ChecksumAlgorithmMD5: {
+			newHash:     md5.New,
+			metaDataKey: keyMD5Checksum,
+			nativeType:  minio.ChecksumNone,
+		},
+		ChecksumAlgorithmSHA256: {
+			newHash:     sha256.New,
+			metaDataKey: checksumMetaDataKey(ChecksumAlgorithmSHA256),
+			nativeType:  minio.ChecksumSHA256,
+		},
+		ChecksumAlgorithmSHA1: {
+			newHash:     sha1.New,
+			metaDataKey: checksumMetaDataKey(ChecksumAlgorithmSHA1),
+			nativeType:  minio.ChecksumSHA1,
+		},
+		ChecksumAlgorithmCRC32: {
+			newHash:     func() hash.Hash { return crc32.NewIEEE() },
+			metaDataKey: checksumMetaDataKey(ChecksumAlgorithmCRC32),
+			nativeType:  minio.ChecksumFullObjectCRC32,
+		},
+		ChecksumAlgorithmCRC64NVME: {
+			newHash:     minio.ChecksumCRC64NVME.Hasher,
+			metaDataKey: checksumMetaDataKey(ChecksumAlgorithmCRC64NVME),
+			nativeType:  minio.ChecksumCRC64NVME,
+		},
+	}
 )
 
+// RegisterChecksumAlgorithm registers a custom checksum algorithm, which can then be enabled using WithIntegritySupport
+// and checked using WithIntegrityCheck. The checksum is stored in the user metadata under the key "Checksum-<algorithm>".
+// The name may only contain letters, digits and dashes.
+func RegisterChecksumAlgorithm(algorithm ChecksumAlgorithm, newHash func() hash.Hash) error {
+	const errMessage = "failed to register checksum algorithm '%s': %w"
+
+	if algorithm == "" || newHash == nil || strings.ContainsFunc(string(algorithm), isInvalidChecksumAlgorithmRune) {
+		return fmt.Errorf(errMessage, algorithm, ErrInvalidChecksumAlgorithm)
+	}
+
+	checksumAlgorithmsMtx.Lock()
+	defer checksumAlgorithmsMtx.Unlock()
+
+	if _, ok := checksumAlgorithms[algorithm]; ok {
+		return fmt.Errorf(errMessage, algorithm, ErrChecksumAlgorithmExists)
+	}
+
+	checksumAlgorithms[algorithm] = &checksumAlgorithm{
+		newHash:     newHash,
+		metaDataKey: checksumMetaDataKey(algorithm),
+		nativeType:  minio.ChecksumNone,
+	}
+
+	return nil
+}
+
+func lookupChecksumAlgorithm(algorithm ChecksumAlgorithm) (*checksumAlgorithm, error) {
+	checksumAlgorithmsMtx.RLock()
+	defer checksumAlgorithmsMtx.RUnlock()
+
+	definition, ok := checksumAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrInvalidChecksumAlgorithm, algorithm)
+	}
+
+	return definition, nil
+}
+
+// registeredChecksumAlgorithms returns a snapshot of all registered checksum algorithms.
+func registeredChecksumAlgorithms() map[ChecksumAlgorithm]*checksumAlgorithm {
+	checksumAlgorithmsMtx.RLock()
+	defer checksumAlgorithmsMtx.RUnlock()
+
+	return maps.Clone(checksumAlgorithms)
+}
+
+func checksumMetaDataKey(algorithm ChecksumAlgorithm) string {
+	return textproto.CanonicalMIMEHeaderKey(keyChecksumPrefix + string(algorithm))
+}
+
+func isInvalidChecksumAlgorithmRune(r rune) bool {
+	return (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '-'
+}
+
 type integritySettings struct {
-	useIntegrityCRC32C bool
-	useIntegrityMD5    bool
+	algorithms         []ChecksumAlgorithm
+	useNativeChecksums bool
 }
 
-// Integrity contains checksums for file integrity.
-type Integrity struct {
-	ChecksumCRC32C string // When CRC32C integrity support is disabled, ChecksumCRC32C will be empty if no explicit integrity check was requested via option
-	ChecksumMD5    string // When MD5 integrity support is disabled, ChecksumMD5 will be empty if no explicit integrity check was requested via option
+func defaultIntegritySettings() integritySettings {
+	return integritySettings{
+		algorithms: []ChecksumAlgorithm{ChecksumAlgorithmCRC32C},
+	}
 }
 
-// GenerateCheckSumCRC32C returns a CRC32C checksum of the given data.
-func GenerateCheckSumCRC32C(data io.Reader) (string, error) {
-	const errMessage = "failed to get CRC32C checksum: %w"
+func (s *integritySettings) setIntegritySupport(algorithm ChecksumAlgorithm, enabled bool) error {
+	if _, err := lookupChecksumAlgorithm(algorithm); err != nil {
+		return err
+	}
 
-	hash := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	s.algorithms = slices.DeleteFunc(slices.Clone(s.algorithms), func(a ChecksumAlgorithm) bool {
+		return a == algorithm
+	})
 
-	if _, err := io.Copy(hash, data); err != nil {
-		return "", fmt.Errorf(errMessage, err)
+	if enabled {
+		s.algorithms = append(s.algorithms, algorithm)
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return nil
 }
 
-// GenerateCheckSumMD5 returns a MD5 checksum of the given data.
-func GenerateCheckSumMD5(data io.Reader) (string, error) {
-	const errMessage = "failed to get MD5 checksum: %w"
+func (s *integritySettings) integrityEnabled() bool {
+	return len(s.algorithms) > 0
+}
 
-	hash := md5.New() //nolint:gosec // intended to use MD5 for hashing
+// nativeAlgorithm returns the first enabled algorithm which is sent in a standard checksum header.
+// S3 only supports one standard checksum per object, all other checksums are stored in the user metadata.
+func (s *integritySettings) nativeAlgorithm() (ChecksumAlgorithm, *checksumAlgorithm) {
+	if !s.useNativeChecksums {
+		return "", nil
+	}
 
-	if _, err := io.Copy(hash, data); err != nil {
-		return "", fmt.Errorf(errMessage, err)
+	for _, algorithm := range s.algorithms {
+		definition, err := lookupChecksumAlgorithm(algorithm)
+		if err == nil && definition.nativeType != minio.ChecksumNone {
+			return algorithm, definition
+		}
 	}
 
-	return hex.EncodeToString(hash.Sum(nil)), nil
+	return "", nil
 }
 
-type checksum string
+// integrityMetaData returns the user metadata for all checksums which have not been stored in the standard checksum headers.
+func (s *integritySettings) integrityMetaData(integrity, native Integrity) map[string]string {
+	metaData := make(map[string]string)
+
+	for _, algorithm := range s.algorithms {
+		checksum := integrity.Checksums[algorithm]
 
-func (c checksum) compareChecksum(expected string) error {
-	if expected != "" && expected != c.hex() {
-		return ErrChecksumMismatch
+		if checksum == "" || checksum == native.Checksums[algorithm] {
+			continue
+		}
+
+		definition, err := lookupChecksumAlgorithm(algorithm)
+		if err != nil {
+			continue
+		}
+
+		metaData[definition.metaDataKey] = checksum
 	}
 
-	return nil
+	return metaData
+}
+
+// Integrity contains checksums for file integrity.
+type Integrity struct {
+	ChecksumCRC32C string // When CRC32C integrity support is disabled, ChecksumCRC32C will be empty if no explicit integrity check was requested via option
+	ChecksumMD5    string // When MD5 integrity support is disabled, ChecksumMD5 will be empty if no explicit integrity check was requested via option
+	// Checksums contains the hex encoded checksums of all enabled algorithms and all algorithms
+	// explicitly checked via option, including CRC32C and MD5.
+	Checksums map[ChecksumAlgorithm]string
 }
 
-func (c checksum) hex() string {
-	return string(c)
+// Checksum returns the hex encoded checksum of the given algorithm or an empty string if it is not present.
+func (i *Integrity) Checksum(algorithm ChecksumAlgorithm) string {
+	return i.Checksums[algorithm]
 }
 
-func getCheckSumCRC32C(obj io.ReadSeeker) (checksum, error) {
-	const errMessage = "failed to get checksum: %w"
+func newIntegrity(checksums map[ChecksumAlgorithm]string) Integrity {
+	if len(checksums) == 0 {
+		return Integrity{}
+	}
 
-	startPos, err := obj.Seek(0, io.SeekStart)
-	if err != nil {
-		return "", fmt.Errorf(errMessage, err)
+	return Integrity{
+		ChecksumCRC32C: checksums[ChecksumAlgorithmCRC32C],
+		ChecksumMD5:    checksums[ChecksumAlgorithmMD5],
+		Checksums:      checksums,
 	}
+}
+
+// GenerateCheckSum returns a checksum of the given data using the given algorithm.
+func GenerateCheckSum(algorithm ChecksumAlgorithm, data io.Reader) (string, error) {
+	const errMessage = "failed to get %s checksum: %w"
 
-	sum, err := GenerateCheckSumCRC32C(obj)
+	hasher, err := newIntegrityHasher([]ChecksumAlgorithm{algorithm})
 	if err != nil {
-		return "", fmt.Errorf(errMessage, err)
+		return "", fmt.Errorf(errMessage, algorithm, err)
 	}
 
-	if _, err := obj.Seek(startPos, io.SeekStart); err != nil {
-		return "", fmt.Errorf(errMessage, err)
+	if _, err := io.Copy(hasher, data); err != nil {
+		return "", fmt.Errorf(errMessage, algorithm, err)
 	}
 
-	return checksum(sum), nil
+	return hasher.integrity().Checksums[algorithm], nil
 }
 
-func getCheckSumMD5(obj io.ReadSeeker) (checksum, error) {
-	const errMessage = "failed to get checksum: %w"
+// GenerateCheckSumCRC32C returns a CRC32C checksum of the given data.
+func GenerateCheckSumCRC32C(data io.Reader) (string, error) {
+	return GenerateCheckSum(ChecksumAlgorithmCRC32C, data)
+}
 
-	startPos, err := obj.Seek(0, io.SeekStart)
-	if err != nil {
-		return "", fmt.Errorf(errMessage, err)
-	}
+// GenerateCheckSumMD5 returns a MD5 checksum of the given data.
+func GenerateCheckSumMD5(data io.Reader) (string, error) {
+	return GenerateCheckSum(ChecksumAlgorithmMD5, data)
+}
 
-	sum, err := GenerateCheckSumMD5(obj)
-	if err != nil {
-		return "", fmt.Errorf(errMessage, err)
+// integrityHasher computes the checksums of all data written to it.
+// This allows checksums to be computed while the data streams to s3 instead of reading it twice.
+type integrityHasher struct {
+	hashes map[ChecksumAlgorithm]hash.Hash
+}
+
+func newIntegrityHasher(algorithms []ChecksumAlgorithm) (*integrityHasher, error) {
+	hasher := &integrityHasher{
+		hashes: make(map[ChecksumAlgorithm]hash.Hash, len(algorithms)),
 	}
 
-	if _, err := obj.Seek(startPos, io.SeekStart); err != nil {
-		return "", fmt.Errorf(errMessage, err)
+	for _, algorithm := range algorithms {
+		definition, err := lookupChecksumAlgorithm(algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		hasher.hashes[algorithm] = definition.newHash()
 	}
 
-	return checksum(sum), nil
+	return hasher, nil
 }
 
-func (c *client) handleIntegrity(obj io.ReadSeeker, info *FileInfo, getOptions *getOptions) error {
-	const errMessage = "failed to handle integrity: %w"
+func (s *integritySettings) newIntegrityHasher() (*integrityHasher, error) {
+	return newIntegrityHasher(s.algorithms)
+}
 
-	params := &handleIntegrityParams{
-		content:    obj,
-		info:       info,
-		getOptions: getOptions,
-		crc32c:     checksum(info.MetaData[keyCR32CChecksum]),
-		md5:        checksum(info.MetaData[keyMD5Checksum]),
+// Write implements the io.Writer interface.
+func (h *integrityHasher) Write(p []byte) (int, error) {
+	for _, hash := range h.hashes {
+		hash.Write(p) //nolint:errcheck // hash.Hash never returns an error
 	}
 
-	if info.MetaData != nil {
-		delete(info.MetaData, keyCR32CChecksum)
-		delete(info.MetaData, keyMD5Checksum)
+	return len(p), nil
+}
+
+// integrity returns the checksums of all data written so far.
+func (h *integrityHasher) integrity() Integrity {
+	checksums := make(map[ChecksumAlgorithm]string, len(h.hashes))
+
+	for algorithm, hash := range h.hashes {
+		checksums[algorithm] = hex.EncodeToString(hash.Sum(nil))
 	}
 
-	if err := c.handleGetFileIntegritySettings(params); err != nil {
-		return fmt.Errorf(errMessage, err)
+	return newIntegrity(checksums)
+}
+
+// nativeIntegrity returns the checksums s3 stored in the standard checksum headers, keyed by their minio checksum type.
+func nativeIntegrity(values map[minio.ChecksumType]string) Integrity {
+	checksums := make(map[ChecksumAlgorithm]string)
+
+	for algorithm, definition := range registeredChecksumAlgorithms() {
+		if definition.nativeType == minio.ChecksumNone {
+			continue
+		}
+
+		if checksum := nativeChecksumHex(values[definition.nativeType.Base()]); checksum != "" {
+			checksums[algorithm] = checksum
+		}
 	}
 
-	if getOptions == nil {
-		return nil // No integrity check options provided, skip integrity options handling
+	return newIntegrity(checksums)
+}
+
+// nativeChecksumHex converts a base64 encoded checksum of the standard checksum headers into its hex representation.
+// Composite checksums of multipart uploads cannot be compared with a checksum of the content and are omitted.
+func nativeChecksumHex(value string) string {
+	if value == "" || strings.Contains(value, "-") {
+		return ""
 	}
 
-	if err := handleGetFileIntegrityOptions(params); err != nil {
-		return fmt.Errorf(errMessage, err)
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return ""
 	}
 
-	return nil
+	return hex.EncodeToString(raw)
 }
 
-type handleIntegrityParams struct {
-	content    io.ReadSeeker
-	getOptions *getOptions
-	info       *FileInfo
-	crc32c     checksum
-	md5        checksum
+// storedChecksums returns the checksums stored with the file, preferring the standard checksum headers over the user metadata.
+// The checksum keys are removed from the metadata of the file info.
+func storedChecksums(info *FileInfo, native Integrity) map[ChecksumAlgorithm]string {
+	checksums := make(map[ChecksumAlgorithm]string)
+
+	for algorithm, definition := range registeredChecksumAlgorithms() {
+		if checksum := cmp.Or(native.Checksums[algorithm], info.MetaData[definition.metaDataKey]); checksum != "" {
+			checksums[algorithm] = checksum
+		}
+
+		delete(info.MetaData, definition.metaDataKey)
+	}
+
+	return checksums
 }
 
-func (c *client) handleGetFileIntegritySettings(params *handleIntegrityParams) error {
-	const errMessage = "failed to handle integrity settings: %w"
+// handleIntegrity sets the checksums stored with the file and returns the content wrapped in a reader,
+// which computes the missing checksums and verifies the content while it is read.
+// Expected checksums contradicting the checksums stored with the file are reported right away.
+func (s *integritySettings) handleIntegrity(
+	content io.ReadCloser,
+	info *FileInfo,
+	native Integrity,
+	getOptions *getOptions,
+) (io.ReadCloser, error) {
+	const errMessage = "failed to handle integrity: %w"
+
+	stored := storedChecksums(info, native)
 
-	var err error
+	required := slices.Clone(s.algorithms)
+	expected := make(map[ChecksumAlgorithm]string)
 
-	if c.useIntegrityCRC32C {
-		if params.crc32c == "" && params.content != nil {
-			params.crc32c, err = getCheckSumCRC32C(params.content)
-			if err != nil {
-				return fmt.Errorf(errMessage, err)
+	if getOptions != nil {
+		for algorithm, checksum := range getOptions.checksums {
+			if _, err := lookupChecksumAlgorithm(algorithm); err != nil {
+				return nil, fmt.Errorf(errMessage, err)
 			}
-		}
 
-		params.info.ChecksumCRC32C = params.crc32c.hex()
-	}
+			if !slices.Contains(required, algorithm) {
+				required = append(required, algorithm)
+			}
+
+			if checksum == "" {
+				continue
+			}
 
-	if c.useIntegrityMD5 {
-		if params.md5 == "" && params.content != nil {
-			params.md5, err = getCheckSumMD5(params.content)
-			if err != nil {
-				return fmt.Errorf(errMessage, err)
+			if stored[algorithm] != "" && stored[algorithm] != checksum {
+				return nil, fmt.Errorf(errMessage, fmt.Errorf("%s: %w", algorithm, ErrChecksumMismatch))
 			}
+
+			expected[algorithm] = checksum
 		}
 
-		params.info.ChecksumMD5 = params.md5.hex()
+		if getOptions.autoVerify {
+			for algorithm, checksum := range stored {
+				if _, ok := expected[algorithm]; !ok {
+					expected[algorithm] = checksum
+				}
+			}
+		}
 	}
 
-	return nil
-}
+	checksums := make(map[ChecksumAlgorithm]string, len(required)+len(expected))
+	hashed := make([]ChecksumAlgorithm, 0, len(required)+len(expected))
 
-func handleGetFileIntegrityOptions(params *handleIntegrityParams) error {
-	const errMessage = "failed to handle integrity options: %w"
+	for _, algorithm := range required {
+		checksums[algorithm] = stored[algorithm]
 
-	var err error
+		if stored[algorithm] == "" {
+			hashed = append(hashed, algorithm)
+		}
+	}
 
-	if params.getOptions.ChecksumCRC32C != "" {
-		err = handleGetFileIntegrityCheckOptionsCRC32C(params)
+	for algorithm := range expected {
+		checksums[algorithm] = stored[algorithm]
+
+		if !slices.Contains(hashed, algorithm) {
+			hashed = append(hashed, algorithm)
+		}
 	}
 
-	if params.getOptions.ChecksumMD5 != "" {
-		err = handleGetFileIntegrityCheckOptionsMD5(params)
+	info.Integrity = newIntegrity(checksums)
+
+	if len(hashed) == 0 {
+		return content, nil
 	}
 
+	hasher, err := newIntegrityHasher(hashed)
 	if err != nil {
-		return fmt.Errorf(errMessage, err)
+		return nil, fmt.Errorf(errMessage, err)
 	}
 
-	return nil
+	reader := &verifyingReader{
+		ReadCloser: content,
+		info:       info,
+		hasher:     hasher,
+		expected:   expected,
+	}
+
+	return reader, nil
 }
 
-func handleGetFileIntegrityCheckOptionsCRC32C(params *handleIntegrityParams) error {
-	const errMessage = "failed to handle cr32c integrity check options: %w"
+func (s *integritySettings) handleGetFileInfoIntegrity(info *FileInfo, native Integrity) {
+	info.Integrity = s.enabledIntegrity(storedChecksums(info, native))
+}
 
-	var err error
+// enabledIntegrity returns the integrity of the enabled algorithms from the given checksums.
+func (s *integritySettings) enabledIntegrity(stored map[ChecksumAlgorithm]string) Integrity {
+	checksums := make(map[ChecksumAlgorithm]string, len(s.algorithms))
 
-	if params.crc32c == "" {
-		params.crc32c, err = getCheckSumCRC32C(params.content)
+	for _, algorithm := range s.algorithms {
+		checksums[algorithm] = stored[algorithm]
+	}
+
+	return newIntegrity(checksums)
+}
+
+// checksumMetaData returns the metadata storing the given checksums, regardless of the enabled algorithms.
+func checksumMetaData(checksums map[ChecksumAlgorithm]string) map[string]string {
+	metaData := make(map[string]string, len(checksums))
+
+	for algorithm, checksum := range checksums {
+		definition, err := lookupChecksumAlgorithm(algorithm)
 		if err != nil {
-			return fmt.Errorf(errMessage, err)
+			continue
 		}
-	}
 
-	if err := params.crc32c.compareChecksum(params.getOptions.ChecksumCRC32C); err != nil {
-		return fmt.Errorf(errMessage, err)
+		metaData[definition.metaDataKey] = checksum
 	}
 
-	params.info.ChecksumCRC32C = params.crc32c.hex()
+	return metaData
+}
 
-	return nil
+// verifyingReader computes the checksums of the content while it is read.
+// Once the content has been read completely, the missing checksums of the file info are set
+// and the content is compared with the expected checksums. A mismatch is returned by the final Read or by Close.
+type verifyingReader struct {
+	io.ReadCloser
+	info     *FileInfo
+	hasher   *integrityHasher
+	expected map[ChecksumAlgorithm]string
+	read     int64
+	verified bool
+	err      error
 }
 
-func handleGetFileIntegrityCheckOptionsMD5(params *handleIntegrityParams) error {
-	const errMessage = "failed to handle md5 integrity options: %w"
+// Read implements the io.Reader interface.
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
 
-	var err error
+	n, err := r.ReadCloser.Read(p)
 
-	if params.md5 == "" {
-		params.md5, err = getCheckSumMD5(params.content)
-		if err != nil {
-			return fmt.Errorf(errMessage, err)
+	r.hasher.Write(p[:n]) //nolint:errcheck // never returns an error
+	r.read += int64(n)
+
+	if errors.Is(err, io.EOF) {
+		if verifyErr := r.verify(); verifyErr != nil {
+			return n, verifyErr
 		}
 	}
 
-	if err := params.md5.compareChecksum(params.getOptions.ChecksumMD5); err != nil {
-		return fmt.Errorf(errMessage, err)
+	return n, err //nolint:wrapcheck // io.EOF must not be wrapped
+}
+
+// Close implements the io.Closer interface.
+// Content which has been read up to its size without reaching io.EOF is verified as well.
+func (r *verifyingReader) Close() error {
+	err := r.ReadCloser.Close()
+
+	if !r.verified && r.read == r.info.Size {
+		r.verify() //nolint:errcheck // the result is kept in r.err
 	}
 
-	params.info.ChecksumMD5 = params.md5.hex()
+	if r.err != nil {
+		return r.err
+	}
 
-	return nil
+	return err //nolint:wrapcheck // passes through the error of the underlying reader
 }
 
-func (c *client) handleGetFileInfoIntegrity(info *FileInfo) {
-	if c.useIntegrityCRC32C {
-		info.ChecksumCRC32C = info.MetaData[keyCR32CChecksum]
+func (r *verifyingReader) verify() error {
+	if r.verified {
+		return r.err
 	}
 
-	if c.useIntegrityMD5 {
-		info.ChecksumMD5 = info.MetaData[keyMD5Checksum]
+	r.verified = true
+
+	computed := r.hasher.integrity()
+
+	checksums := maps.Clone(r.info.Checksums)
+
+	for algorithm, checksum := range computed.Checksums {
+		if checksums[algorithm] == "" {
+			checksums[algorithm] = checksum
+		}
 	}
 
-	if info.MetaData != nil {
-		delete(info.MetaData, keyCR32CChecksum)
-		delete(info.MetaData, keyMD5Checksum)
+	r.info.Integrity = newIntegrity(checksums)
+
+	for algorithm, expected := range r.expected {
+		if computed.Checksums[algorithm] != expected {
+			r.err = fmt.Errorf("failed to verify integrity: %s: %w", algorithm, ErrChecksumMismatch)
+
+			return r.err
+		}
 	}
+
+	return nil
 }
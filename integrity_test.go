@@ -3,11 +3,16 @@ package s3_test //nolint:revive // package name matches folder name
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
+	"hash"
+	"hash/fnv"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/Clarilab/s3-client/v4"
 	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
 	"github.com/stretchr/testify/require"
 )
 
@@ -45,6 +50,73 @@ func Test_GetChecksumMD5(t *testing.T) {
 	}
 }
 
+func Test_GenerateCheckSum(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[s3.ChecksumAlgorithm]string{
+		s3.ChecksumAlgorithmCRC32C:    "4748d6bb",
+		s3.ChecksumAlgorithmMD5:       "443217297805b7b46584cea3c26980f0",
+		s3.ChecksumAlgorithmSHA256:    "13bdcc106245afe03ba86ab06052aae39e836c42e1951f198e012aeba287e3d7",
+		s3.ChecksumAlgorithmSHA1:      "5d5d81a345bc33716e2edf2d7dccf988868b1c67",
+		s3.ChecksumAlgorithmCRC32:     "71eae6a0",
+		s3.ChecksumAlgorithmCRC64NVME: "34fc847a084c6842",
+	}
+
+	for algorithm, expectedChecksum := range testCases {
+		t.Run(string(algorithm), func(t *testing.T) {
+			t.Parallel()
+
+			checksum, err := s3.GenerateCheckSum(algorithm, strings.NewReader("asdfqweryxcv"))
+			require.NoError(t, err)
+			require.Equal(t, expectedChecksum, checksum)
+		})
+	}
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.GenerateCheckSum("unknown", strings.NewReader("asdfqweryxcv"))
+		require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+	})
+}
+
+func Test_RegisterChecksumAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	const algorithm s3.ChecksumAlgorithm = "FNV64A-TEST"
+
+	err := s3.RegisterChecksumAlgorithm(algorithm, func() hash.Hash { return fnv.New64a() })
+	require.NoError(t, err)
+
+	checksum, err := s3.GenerateCheckSum(algorithm, strings.NewReader("asdfqweryxcv"))
+	require.NoError(t, err)
+
+	expected := fnv.New64a()
+	expected.Write([]byte("asdfqweryxcv"))
+
+	require.Equal(t, hex.EncodeToString(expected.Sum(nil)), checksum)
+
+	err = s3.RegisterChecksumAlgorithm(algorithm, func() hash.Hash { return fnv.New64a() })
+	require.ErrorIs(t, err, s3.ErrChecksumAlgorithmExists)
+
+	err = s3.RegisterChecksumAlgorithm("invalid name", func() hash.Hash { return fnv.New64a() })
+	require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+
+	s3Client, err := s3.NewMemoryClient(bucketName, s3.WithIntegritySupport(algorithm, true))
+	require.NoError(t, err)
+
+	uploaded := uploadTestFileWithClient(t, s3Client, "test-custom-checksum-algorithm", testFile1Name)
+
+	fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, fileInfo.Checksum(algorithm))
+	require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+
+	_, err = s3.NewMemoryClient(bucketName, s3.WithIntegritySupport("unknown", true))
+	require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+}
+
 func Test_Integrity_UploadFile(t *testing.T) {
 	t.Parallel()
 
@@ -106,6 +178,28 @@ func Test_Integrity_UploadFile(t *testing.T) {
 		require.Equal(t, expectedChecksum, info.ChecksumMD5)
 	})
 
+	t.Run("upload file reads content once", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t, s3.WithMD5IntegritySupport(true))
+
+		testUploadReadsContentOnce(t, s3Client, folder)
+	})
+
+	t.Run("upload file with multiple checksum algorithms", func(t *testing.T) {
+		t.Parallel()
+
+		testMultipleChecksumAlgorithms(t, getS3Client, folder)
+	})
+
+	t.Run("upload stream with crc32c integrity support", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		testUploadStream(t, s3Client, folder)
+	})
+
 	t.Run("upload file without crc32c integrity support", func(t *testing.T) {
 		t.Parallel()
 
@@ -335,6 +429,32 @@ func Test_Integrity_GetFile(t *testing.T) {
 		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
 	})
 
+	t.Run("auto integrity check detects overwritten content", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+
+		objInfo, err := minioClient.StatObject(context.Background(), bucketName, uploaded.filePath, minio.StatObjectOptions{})
+		require.NoError(t, err)
+
+		corrupted := bytes.ToUpper(uploaded.content)
+
+		// replace the content while keeping the checksum metadata of the original content
+		_, err = minioClient.PutObject(context.Background(), bucketName, uploaded.filePath,
+			bytes.NewReader(corrupted), int64(len(corrupted)),
+			minio.PutObjectOptions{ContentType: contentType, UserMetadata: objInfo.UserMetadata},
+		)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		_, err = file.Bytes()
+		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
+	})
+
 	t.Run("invalid md5 checksum", func(t *testing.T) {
 		t.Parallel()
 
@@ -342,7 +462,10 @@ func Test_Integrity_GetFile(t *testing.T) {
 
 		uploaded := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
 
-		_, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckMD5("invalid-checksum"))
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckMD5("invalid-checksum"))
+		require.NoError(t, err)
+
+		_, err = file.Bytes()
 		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
 	})
 }
@@ -431,6 +554,159 @@ func Test_Integrity_GetFileInfo(t *testing.T) {
 	})
 }
 
+func testUploadReadsContentOnce(t *testing.T, s3Client s3.Client, s3Folder string) {
+	t.Helper()
+
+	content, err := testData.ReadFile(testDataFolder + "/" + testFile1Name)
+	require.NoError(t, err)
+
+	lenTestFile := int64(len(content))
+
+	reader := &countingReadSeeker{ReadSeeker: bytes.NewReader(content)}
+
+	upload := s3.NewUpload(reader, &lenTestFile, s3Folder+"/"+uuid.NewString(), contentType, nil)
+
+	expectedCRC32C, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	expectedMD5, err := s3.GenerateCheckSumMD5(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	info, err := s3Client.UploadFile(context.Background(), upload)
+	require.NoError(t, err)
+
+	require.Equal(t, lenTestFile, reader.bytesRead)
+	require.Equal(t, expectedCRC32C, info.ChecksumCRC32C)
+	require.Equal(t, expectedMD5, info.ChecksumMD5)
+
+	fileInfo, err := s3Client.GetFileInfo(context.Background(), upload.Path)
+	require.NoError(t, err)
+
+	require.Equal(t, expectedCRC32C, fileInfo.ChecksumCRC32C)
+	require.Equal(t, expectedMD5, fileInfo.ChecksumMD5)
+	require.Equal(t, contentType, fileInfo.ContentType)
+}
+
+func testUploadStream(t *testing.T, s3Client s3.Client, s3Folder string) {
+	t.Helper()
+
+	content, err := testData.ReadFile(testDataFolder + "/" + testFile2Name)
+	require.NoError(t, err)
+
+	metaData := map[string]string{headerFileName: testFile2Name}
+
+	pipeReader, pipeWriter := io.Pipe()
+
+	go func() {
+		_, err := pipeWriter.Write(content)
+		pipeWriter.CloseWithError(err)
+	}()
+
+	upload := s3.NewStreamUpload(pipeReader, s3Folder+"/"+uuid.NewString(), contentType, metaData)
+
+	expectedChecksum, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	info, err := s3Client.UploadStream(context.Background(), upload)
+	require.NoError(t, err)
+
+	require.Equal(t, int64(len(content)), info.Size)
+	require.Equal(t, expectedChecksum, info.ChecksumCRC32C)
+
+	file, err := s3Client.GetFile(context.Background(), upload.Path)
+	require.NoError(t, err)
+
+	fileContent, err := file.Bytes()
+	require.NoError(t, err)
+
+	fileInfo := file.Info()
+
+	require.Equal(t, content, fileContent)
+	require.Equal(t, contentType, fileInfo.ContentType)
+	require.Equal(t, metaData, fileInfo.MetaData)
+	require.Equal(t, expectedChecksum, fileInfo.ChecksumCRC32C)
+}
+
+func testMultipleChecksumAlgorithms(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
+
+	algorithms := []s3.ChecksumAlgorithm{
+		s3.ChecksumAlgorithmCRC32C,
+		s3.ChecksumAlgorithmSHA256,
+		s3.ChecksumAlgorithmSHA1,
+		s3.ChecksumAlgorithmCRC32,
+		s3.ChecksumAlgorithmCRC64NVME,
+	}
+
+	options := make([]s3.ClientOption, 0, len(algorithms))
+
+	for _, algorithm := range algorithms {
+		options = append(options, s3.WithIntegritySupport(algorithm, true))
+	}
+
+	s3Client := newClient(t, options...)
+
+	content, err := testData.ReadFile(testDataFolder + "/" + testFile1Name)
+	require.NoError(t, err)
+
+	lenTestFile := int64(len(content))
+
+	metaData := map[string]string{headerFileName: testFile1Name}
+
+	upload := s3.NewUpload(bytes.NewReader(content), &lenTestFile, s3Folder+"/"+uuid.NewString(), contentType, metaData)
+
+	info, err := s3Client.UploadFile(context.Background(), upload)
+	require.NoError(t, err)
+
+	fileInfo, err := s3Client.GetFileInfo(context.Background(), upload.Path)
+	require.NoError(t, err)
+
+	require.Equal(t, metaData, fileInfo.MetaData)
+	require.Len(t, info.Checksums, len(algorithms))
+
+	for _, algorithm := range algorithms {
+		expectedChecksum, err := s3.GenerateCheckSum(algorithm, bytes.NewReader(content))
+		require.NoError(t, err)
+
+		require.Equal(t, expectedChecksum, info.Checksum(algorithm))
+		require.Equal(t, expectedChecksum, fileInfo.Checksum(algorithm))
+	}
+
+	expectedMD5, err := s3.GenerateCheckSumMD5(bytes.NewReader(content))
+	require.NoError(t, err)
+
+	file, err := s3Client.GetFile(context.Background(), upload.Path,
+		s3.WithIntegrityCheck(s3.ChecksumAlgorithmSHA256, info.Checksum(s3.ChecksumAlgorithmSHA256)),
+		s3.WithIntegrityCheck(s3.ChecksumAlgorithmMD5, expectedMD5),
+	)
+	require.NoError(t, err)
+
+	fileContent, err := file.Bytes()
+	require.NoError(t, err)
+
+	require.Equal(t, content, fileContent)
+	require.Equal(t, expectedMD5, file.Info().ChecksumMD5)
+	require.Equal(t, info.Checksums[s3.ChecksumAlgorithmCRC64NVME], file.Info().Checksum(s3.ChecksumAlgorithmCRC64NVME))
+
+	_, err = s3Client.GetFile(context.Background(), upload.Path, s3.WithIntegrityCheck(s3.ChecksumAlgorithmSHA1, "invalid-checksum"))
+	require.ErrorIs(t, err, s3.ErrChecksumMismatch)
+
+	_, err = s3Client.GetFile(context.Background(), upload.Path, s3.WithIntegrityCheck("unknown", "invalid-checksum"))
+	require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+}
+
+type countingReadSeeker struct {
+	io.ReadSeeker
+	bytesRead int64
+}
+
+func (r *countingReadSeeker) Read(p []byte) (int, error) {
+	n, err := r.ReadSeeker.Read(p)
+	r.bytesRead += int64(n)
+
+	return n, err
+}
+
 func uploadTestFileWithClient(t *testing.T, client s3.Client, s3Folder, testFileName string) *uploaded {
 	t.Helper()
 
@@ -461,3 +737,117 @@ func uploadTestFileWithClient(t *testing.T, client s3.Client, s3Folder, testFile
 
 	return uploaded
 }
+
+func Test_Integrity_NativeChecksums(t *testing.T) {
+	t.Parallel()
+
+	const folder = "test-integrity-native-checksums"
+
+	t.Run("upload file with native crc32c checksum", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t, s3.WithNativeChecksums(true))
+
+		uploaded := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+
+		expectedChecksum, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		objInfo, err := minioClient.StatObject(context.Background(), bucketName, uploaded.filePath, minio.StatObjectOptions{Checksum: true})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, objInfo.ChecksumCRC32C)
+		require.NotContains(t, objInfo.UserMetadata, "Checksum-Cr32c")
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumCRC32C)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckCRC32C(expectedChecksum))
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.content, fileContent)
+		require.Equal(t, expectedChecksum, file.Info().ChecksumCRC32C)
+	})
+
+	t.Run("upload stream with native crc32c checksum", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t, s3.WithNativeChecksums(true))
+
+		testUploadStream(t, s3Client, folder)
+	})
+
+	t.Run("md5 checksum is stored in metadata", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t, s3.WithNativeChecksums(true), s3.WithMD5IntegritySupport(true))
+
+		uploaded := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+
+		expectedChecksum, err := s3.GenerateCheckSumMD5(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		objInfo, err := minioClient.StatObject(context.Background(), bucketName, uploaded.filePath, minio.StatObjectOptions{Checksum: true})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, objInfo.ChecksumCRC32C)
+		require.Equal(t, expectedChecksum, objInfo.UserMetadata["Checksum-Md5"])
+	})
+
+	t.Run("upload file with native sha256 checksum", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getS3Client(t,
+			s3.WithNativeChecksums(true),
+			s3.WithCRC32CIntegritySupport(false),
+			s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true),
+			s3.WithIntegritySupport(s3.ChecksumAlgorithmCRC32C, true),
+		)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+
+		expectedSHA256, err := s3.GenerateCheckSum(s3.ChecksumAlgorithmSHA256, bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		expectedCRC32C, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		objInfo, err := minioClient.StatObject(context.Background(), bucketName, uploaded.filePath, minio.StatObjectOptions{Checksum: true})
+		require.NoError(t, err)
+
+		require.NotEmpty(t, objInfo.ChecksumSHA256)
+		require.NotContains(t, objInfo.UserMetadata, "Checksum-Sha256")
+		require.Equal(t, expectedCRC32C, objInfo.UserMetadata["Checksum-Cr32c"])
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedSHA256, fileInfo.Checksum(s3.ChecksumAlgorithmSHA256))
+		require.Equal(t, expectedCRC32C, fileInfo.ChecksumCRC32C)
+	})
+
+	t.Run("legacy metadata checksum fallback", func(t *testing.T) {
+		t.Parallel()
+
+		legacyClient := getS3Client(t)
+		s3Client := getS3Client(t, s3.WithNativeChecksums(true))
+
+		uploaded := uploadTestFileWithClient(t, legacyClient, folder, testFile2Name)
+
+		expectedChecksum, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumCRC32C)
+	})
+}
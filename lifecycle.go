@@ -0,0 +1,249 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+const (
+	lifecycleStatusEnabled  = "Enabled"
+	lifecycleStatusDisabled = "Disabled"
+)
+
+// LifecycleRule is a lifecycle rule of the bucket.
+// A rule applies to all objects under the prefix which carry all of the tags and needs at least one action.
+type LifecycleRule struct {
+	ID       string
+	Prefix   string
+	Tags     map[string]string
+	Disabled bool
+
+	// ExpirationDays deletes objects the given number of days after their creation.
+	ExpirationDays int
+	// Transition moves objects to another storage class.
+	Transition *LifecycleTransition
+	// NoncurrentVersionExpirationDays deletes noncurrent versions the given number of days after they became noncurrent.
+	NoncurrentVersionExpirationDays int
+	// NewerNoncurrentVersions keeps the given number of newest noncurrent versions from expiring.
+	NewerNoncurrentVersions int
+	// NoncurrentVersionTransition moves noncurrent versions to another storage class,
+	// the days count from the moment they became noncurrent.
+	NoncurrentVersionTransition *LifecycleTransition
+	// AbortIncompleteMultipartUploadDays aborts multipart uploads which did not complete within the given number of days.
+	AbortIncompleteMultipartUploadDays int
+}
+
+// LifecycleTransition moves objects to another storage class after the given number of days.
+type LifecycleTransition struct {
+	Days         int
+	StorageClass string
+}
+
+func (r *LifecycleRule) validate() error {
+	const errMessage = "%w: %s"
+
+	switch {
+	case r.ID == "":
+		return fmt.Errorf(errMessage, ErrInvalidLifecycleRule, "missing rule id")
+	case r.ExpirationDays < 0 || r.NoncurrentVersionExpirationDays < 0 || r.AbortIncompleteMultipartUploadDays < 0:
+		return fmt.Errorf(errMessage, ErrInvalidLifecycleRule, "negative days")
+	case r.NewerNoncurrentVersions > 0 && r.NoncurrentVersionExpirationDays == 0:
+		return fmt.Errorf(errMessage, ErrInvalidLifecycleRule, "newer noncurrent versions require a noncurrent version expiration")
+	case r.Transition != nil && r.Transition.StorageClass == "",
+		r.NoncurrentVersionTransition != nil && r.NoncurrentVersionTransition.StorageClass == "":
+		return fmt.Errorf(errMessage, ErrInvalidLifecycleRule, "missing transition storage class")
+	case r.ExpirationDays == 0 && r.Transition == nil && r.NoncurrentVersionExpirationDays == 0 &&
+		r.NoncurrentVersionTransition == nil && r.AbortIncompleteMultipartUploadDays == 0:
+		return fmt.Errorf(errMessage, ErrInvalidLifecycleRule, "missing action")
+	}
+
+	return nil
+}
+
+// lifecycleRule converts the rule into its s3 representation.
+func (r *LifecycleRule) lifecycleRule() lifecycle.Rule {
+	rule := lifecycle.Rule{
+		ID:         r.ID,
+		Status:     lifecycleStatusEnabled,
+		RuleFilter: r.filter(),
+		Expiration: lifecycle.Expiration{
+			Days: lifecycle.ExpirationDays(r.ExpirationDays),
+		},
+		NoncurrentVersionExpiration: lifecycle.NoncurrentVersionExpiration{
+			NoncurrentDays:          lifecycle.ExpirationDays(r.NoncurrentVersionExpirationDays),
+			NewerNoncurrentVersions: r.NewerNoncurrentVersions,
+		},
+		AbortIncompleteMultipartUpload: lifecycle.AbortIncompleteMultipartUpload{
+			DaysAfterInitiation: lifecycle.ExpirationDays(r.AbortIncompleteMultipartUploadDays),
+		},
+	}
+
+	if r.Disabled {
+		rule.Status = lifecycleStatusDisabled
+	}
+
+	if r.Transition != nil {
+		rule.Transition = lifecycle.Transition{
+			Days:         lifecycle.ExpirationDays(r.Transition.Days),
+			StorageClass: r.Transition.StorageClass,
+		}
+	}
+
+	if r.NoncurrentVersionTransition != nil {
+		rule.NoncurrentVersionTransition = lifecycle.NoncurrentVersionTransition{
+			NoncurrentDays: lifecycle.ExpirationDays(r.NoncurrentVersionTransition.Days),
+			StorageClass:   r.NoncurrentVersionTransition.StorageClass,
+		}
+	}
+
+	return rule
+}
+
+// filter returns the s3 filter of the rule. S3 only allows a single condition per filter,
+// multiple conditions have to be combined using And.
+func (r *LifecycleRule) filter() lifecycle.Filter {
+	tags := make([]lifecycle.Tag, 0, len(r.Tags))
+
+	for _, key := range slices.Sorted(maps.Keys(r.Tags)) {
+		tags = append(tags, lifecycle.Tag{Key: key, Value: r.Tags[key]})
+	}
+
+	switch {
+	case len(tags) == 0:
+		return lifecycle.Filter{Prefix: r.Prefix}
+	case len(tags) == 1 && r.Prefix == "":
+		return lifecycle.Filter{Tag: tags[0]}
+	default:
+		return lifecycle.Filter{And: lifecycle.And{Prefix: r.Prefix, Tags: tags}}
+	}
+}
+
+// newLifecycleRule converts the s3 representation of a rule.
+func newLifecycleRule(rule *lifecycle.Rule) *LifecycleRule {
+	result := &LifecycleRule{
+		ID:                                 rule.ID,
+		Prefix:                             cmp.Or(rule.RuleFilter.And.Prefix, rule.RuleFilter.Prefix, rule.Prefix),
+		Disabled:                           rule.Status == lifecycleStatusDisabled,
+		ExpirationDays:                     int(rule.Expiration.Days),
+		NoncurrentVersionExpirationDays:    int(rule.NoncurrentVersionExpiration.NoncurrentDays),
+		NewerNoncurrentVersions:            rule.NoncurrentVersionExpiration.NewerNoncurrentVersions,
+		AbortIncompleteMultipartUploadDays: int(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation),
+	}
+
+	tags := rule.RuleFilter.And.Tags
+
+	if !rule.RuleFilter.Tag.IsEmpty() {
+		tags = append(tags, rule.RuleFilter.Tag)
+	}
+
+	if len(tags) > 0 {
+		result.Tags = make(map[string]string, len(tags))
+
+		for _, tag := range tags {
+			result.Tags[tag.Key] = tag.Value
+		}
+	}
+
+	if !rule.Transition.IsNull() {
+		result.Transition = &LifecycleTransition{
+			Days:         int(rule.Transition.Days),
+			StorageClass: rule.Transition.StorageClass,
+		}
+	}
+
+	if !rule.NoncurrentVersionTransition.IsStorageClassEmpty() {
+		result.NoncurrentVersionTransition = &LifecycleTransition{
+			Days:         int(rule.NoncurrentVersionTransition.NoncurrentDays),
+			StorageClass: rule.NoncurrentVersionTransition.StorageClass,
+		}
+	}
+
+	return result
+}
+
+func lifecycleRules(config *lifecycle.Configuration) []*LifecycleRule {
+	rules := make([]*LifecycleRule, 0, len(config.Rules))
+
+	for i := range config.Rules {
+		rules = append(rules, newLifecycleRule(&config.Rules[i]))
+	}
+
+	return rules
+}
+
+func findLifecycleRule(config *lifecycle.Configuration, ruleID string) (*LifecycleRule, error) {
+	index := slices.IndexFunc(config.Rules, func(rule lifecycle.Rule) bool {
+		return rule.ID == ruleID
+	})
+
+	if index < 0 {
+		return nil, fmt.Errorf("%w: '%s'", ErrLifecycleRuleNotFound, ruleID)
+	}
+
+	return newLifecycleRule(&config.Rules[index]), nil
+}
+
+// putLifecycleRule replaces the rule with the same id or appends the rule, keeping all other rules untouched.
+func putLifecycleRule(config *lifecycle.Configuration, rule *LifecycleRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+
+	normalizeLifecycleRules(config)
+
+	index := slices.IndexFunc(config.Rules, func(existing lifecycle.Rule) bool {
+		return existing.ID == rule.ID
+	})
+
+	if index < 0 {
+		config.Rules = append(config.Rules, rule.lifecycleRule())
+	} else {
+		config.Rules[index] = rule.lifecycleRule()
+	}
+
+	return nil
+}
+
+// removeLifecycleRule removes the rule with the given id, keeping all other rules untouched.
+func removeLifecycleRule(config *lifecycle.Configuration, ruleID string) error {
+	normalizeLifecycleRules(config)
+
+	rules := slices.DeleteFunc(config.Rules, func(rule lifecycle.Rule) bool {
+		return rule.ID == ruleID
+	})
+
+	if len(rules) == len(config.Rules) {
+		return fmt.Errorf("%w: '%s'", ErrLifecycleRuleNotFound, ruleID)
+	}
+
+	config.Rules = rules
+
+	return nil
+}
+
+// normalizeLifecycleRules moves the deprecated prefix of rules into their filter,
+// since s3 rejects configurations containing rules with both.
+func normalizeLifecycleRules(config *lifecycle.Configuration) {
+	for i := range config.Rules {
+		rule := &config.Rules[i]
+
+		if rule.Prefix != "" && rule.RuleFilter.IsNull() {
+			rule.RuleFilter.Prefix = rule.Prefix
+			rule.Prefix = ""
+		}
+	}
+}
+
+// folderPrefix returns the prefix of all objects in the given folder.
+func folderPrefix(folderPath string) string {
+	if !strings.HasSuffix(folderPath, "/") {
+		folderPath += "/"
+	}
+
+	return folderPath
+}
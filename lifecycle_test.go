@@ -0,0 +1,131 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LifecycleRules(t *testing.T) {
+	t.Parallel()
+
+	testLifecycleRules(t, getS3Client(t))
+}
+
+func testLifecycleRules(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	expirationRule := &s3.LifecycleRule{
+		ID:             uuid.NewString(),
+		Prefix:         "test-lifecycle/expire/",
+		ExpirationDays: 7,
+	}
+
+	taggedRule := &s3.LifecycleRule{
+		ID:                              uuid.NewString(),
+		Prefix:                          "test-lifecycle/tagged/",
+		Tags:                            map[string]string{"retention": "short", "team": "billing"},
+		ExpirationDays:                  30,
+		NoncurrentVersionExpirationDays: 3,
+	}
+
+	err := s3Client.PutLifecycleRule(ctx, expirationRule)
+	require.NoError(t, err)
+
+	err = s3Client.PutLifecycleRule(ctx, taggedRule)
+	require.NoError(t, err)
+
+	// adding a rule must not remove the existing rules
+	addedRuleID := uuid.NewString()
+
+	err = s3Client.AddLifeCycleRule(ctx, addedRuleID, "test-lifecycle/added", 1)
+	require.NoError(t, err)
+
+	rules, err := s3Client.GetLifecycleRules(ctx)
+	require.NoError(t, err)
+
+	ruleIDs := make([]string, 0, len(rules))
+
+	for _, rule := range rules {
+		ruleIDs = append(ruleIDs, rule.ID)
+	}
+
+	require.Subset(t, ruleIDs, []string{expirationRule.ID, taggedRule.ID, addedRuleID})
+
+	rule, err := s3Client.GetLifecycleRule(ctx, taggedRule.ID)
+	require.NoError(t, err)
+	require.Equal(t, taggedRule, rule)
+
+	rule, err = s3Client.GetLifecycleRule(ctx, addedRuleID)
+	require.NoError(t, err)
+	require.Equal(t, "test-lifecycle/added/", rule.Prefix)
+	require.Equal(t, 1, rule.ExpirationDays)
+
+	// replace a rule
+	expirationRule.ExpirationDays = 14
+	expirationRule.Tags = map[string]string{"retention": "long"}
+
+	err = s3Client.PutLifecycleRule(ctx, expirationRule)
+	require.NoError(t, err)
+
+	rule, err = s3Client.GetLifecycleRule(ctx, expirationRule.ID)
+	require.NoError(t, err)
+	require.Equal(t, expirationRule, rule)
+
+	// remove the rules
+	for _, ruleID := range []string{expirationRule.ID, taggedRule.ID, addedRuleID} {
+		err = s3Client.RemoveLifecycleRule(ctx, ruleID)
+		require.NoError(t, err)
+
+		_, err = s3Client.GetLifecycleRule(ctx, ruleID)
+		require.ErrorIs(t, err, s3.ErrLifecycleRuleNotFound)
+	}
+
+	err = s3Client.RemoveLifecycleRule(ctx, expirationRule.ID)
+	require.ErrorIs(t, err, s3.ErrLifecycleRuleNotFound)
+
+	err = s3Client.PutLifecycleRule(ctx, &s3.LifecycleRule{ID: uuid.NewString(), Prefix: "test-lifecycle/"})
+	require.ErrorIs(t, err, s3.ErrInvalidLifecycleRule)
+}
+
+func testLifecycleRuleActions(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	rule := &s3.LifecycleRule{
+		ID:       uuid.NewString(),
+		Tags:     map[string]string{"archive": "true"},
+		Disabled: true,
+		Transition: &s3.LifecycleTransition{
+			Days:         30,
+			StorageClass: "GLACIER",
+		},
+		NoncurrentVersionExpirationDays: 90,
+		NewerNoncurrentVersions:         2,
+		NoncurrentVersionTransition: &s3.LifecycleTransition{
+			Days:         10,
+			StorageClass: "STANDARD_IA",
+		},
+		AbortIncompleteMultipartUploadDays: 2,
+	}
+
+	err := s3Client.PutLifecycleRule(ctx, rule)
+	require.NoError(t, err)
+
+	result, err := s3Client.GetLifecycleRule(ctx, rule.ID)
+	require.NoError(t, err)
+	require.Equal(t, rule, result)
+
+	err = s3Client.PutLifecycleRule(ctx, &s3.LifecycleRule{
+		ID:                      uuid.NewString(),
+		ExpirationDays:          1,
+		NewerNoncurrentVersions: 1,
+	})
+	require.ErrorIs(t, err, s3.ErrInvalidLifecycleRule)
+}
@@ -0,0 +1,172 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"cmp"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+const defaultPageSize = 1000
+
+// FilePage is a page of files returned by ListFilesPage.
+type FilePage struct {
+	Files []*FileInfo
+	// ContinuationToken is an opaque token which returns the next page when passed to WithContinuationToken.
+	// It is empty when there are no more files.
+	ContinuationToken string
+}
+
+// continuationToken is the content of an opaque continuation token.
+// The prefix is kept to reject tokens which have been issued for another listing.
+type continuationToken struct {
+	Prefix     string `json:"p"`
+	StartAfter string `json:"s"`
+}
+
+func newContinuationToken(prefix, startAfter string) string {
+	data, _ := json.Marshal(&continuationToken{Prefix: prefix, StartAfter: startAfter}) //nolint:errchkjson // cannot fail
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func parseContinuationToken(prefix, token string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", ErrInvalidContinuationToken
+	}
+
+	parsed := new(continuationToken)
+
+	if err := json.Unmarshal(data, parsed); err != nil || parsed.Prefix != prefix {
+		return "", ErrInvalidContinuationToken
+	}
+
+	return parsed.StartAfter, nil
+}
+
+func newListOptions(options []ListOption) *listOptions {
+	opts := &listOptions{
+		pageSize: defaultPageSize,
+	}
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	return opts
+}
+
+// startKey returns the key after which the listing starts. A continuation token takes precedence over StartAfter.
+func (o *listOptions) startKey(prefix string) (string, error) {
+	if o.continuationToken == "" {
+		return o.startAfter, nil
+	}
+
+	return parseContinuationToken(prefix, o.continuationToken)
+}
+
+// limitReached reports whether the given number of listed files reached the max keys.
+func (o *listOptions) limitReached(count int) bool {
+	return o.maxKeys > 0 && count >= o.maxKeys
+}
+
+// listedFilesFunc lists the files under the prefix after the given key, in the order of their paths.
+type listedFilesFunc func(ctx context.Context, prefix, startAfter string, pageSize int) iter.Seq2[*FileInfo, error]
+
+// describeFileFunc returns the file info of a listed file with the details requested by the options.
+type describeFileFunc func(ctx context.Context, listed *FileInfo, opts *listOptions) (*FileInfo, error)
+
+// listFiles lists the files under the prefix from the start key of the options up to the max keys.
+// Files removed while listing are skipped.
+func listFiles(
+	ctx context.Context,
+	prefix string,
+	options []ListOption,
+	list listedFilesFunc,
+	describe describeFileFunc,
+) iter.Seq2[*FileInfo, error] {
+	const errMessage = "failed to list files: %w"
+
+	opts := newListOptions(options)
+
+	return func(yield func(*FileInfo, error) bool) {
+		startAfter, err := opts.startKey(prefix)
+		if err != nil {
+			yield(nil, fmt.Errorf(errMessage, err))
+
+			return
+		}
+
+		count := 0
+
+		for listed, err := range list(ctx, prefix, startAfter, opts.pageSize) {
+			if err != nil {
+				yield(nil, fmt.Errorf(errMessage, err))
+
+				return
+			}
+
+			if opts.limitReached(count) {
+				return
+			}
+
+			if err := ctx.Err(); err != nil {
+				yield(nil, fmt.Errorf(errMessage, err))
+
+				return
+			}
+
+			info, err := describe(ctx, listed, opts)
+			if errors.Is(err, ErrNotFound) {
+				continue // removed while listing
+			}
+
+			if err != nil {
+				yield(nil, fmt.Errorf(errMessage, err))
+
+				return
+			}
+
+			count++
+
+			if !yield(info, nil) {
+				return
+			}
+		}
+	}
+}
+
+type listFilesFunc func(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileInfo, error]
+
+// listFilesPage collects a single page of the listing. One more file than the page holds is listed
+// to find out whether a continuation token is needed.
+func listFilesPage(ctx context.Context, listFiles listFilesFunc, prefix string, options ...ListOption) (*FilePage, error) {
+	const errMessage = "failed to list files page: %w"
+
+	limit := cmp.Or(newListOptions(options).maxKeys, defaultPageSize)
+
+	page := &FilePage{
+		Files: make([]*FileInfo, 0, limit),
+	}
+
+	for info, err := range listFiles(ctx, prefix, slices.Concat(options, []ListOption{WithMaxKeys(limit + 1)})...) {
+		if err != nil {
+			return nil, fmt.Errorf(errMessage, err)
+		}
+
+		if len(page.Files) == limit {
+			page.ContinuationToken = newContinuationToken(prefix, page.Files[limit-1].Path)
+
+			break
+		}
+
+		page.Files = append(page.Files, info)
+	}
+
+	return page, nil
+}
@@ -0,0 +1,449 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+const (
+	localLinkScheme      = "file"
+	localMetaDataFolder  = ".s3-client"
+	localObjectsFolder   = "objects"
+	localTempFolder      = "tmp"
+	localLifecycleFile   = "lifecycle.xml"
+	localTempFilePattern = "upload-*"
+)
+
+type localStore struct {
+	rootPath string
+	mtx      sync.RWMutex
+}
+
+// localMetaData is the content of the sidecar file stored next to every object.
+type localMetaData struct {
+	ContentType string            `json:"contentType"`
+	MetaData    map[string]string `json:"metaData"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// NewLocalClient instantiates a s3 client which stores all objects in the given local folder.
+// Object paths map to files below rootPath, while content types, metadata and checksums
+// are kept in sidecar files inside the hidden ".s3-client" folder.
+func NewLocalClient(rootPath string, options ...ClientOption) (Client, error) {
+	const errMessage = "failed to create local s3 client: %w"
+
+	if rootPath == "" {
+		return nil, fmt.Errorf(errMessage, ErrEmptyRootPath)
+	}
+
+	absPath, err := filepath.Abs(rootPath)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(absPath, localMetaDataFolder, localTempFolder), 0o700); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	client, err := newStoreClient(&localStore{rootPath: absPath}, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return client, nil
+}
+
+func (s *localStore) putObject(path string, content io.Reader, attrs func() *objectAttributes) (int64, error) {
+	objectPath, err := s.objectPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	tempObject, size, err := s.writeTempFile(content)
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(tempObject)
+
+	objectAttrs := attrs()
+
+	metaData, err := json.Marshal(&localMetaData{
+		ContentType: objectAttrs.contentType,
+		MetaData:    objectAttrs.metaData,
+		Tags:        objectAttrs.tags,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	tempMetaData, _, err := s.writeTempFile(bytes.NewReader(metaData))
+	if err != nil {
+		return 0, err
+	}
+
+	defer os.Remove(tempMetaData)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := renameFile(tempMetaData, s.metaDataPath(path)); err != nil {
+		return 0, err
+	}
+
+	if err := renameFile(tempObject, objectPath); err != nil {
+		return 0, err
+	}
+
+	return size, nil
+}
+
+func (s *localStore) getObject(path string) (io.ReadSeekCloser, *objectAttributes, error) {
+	objectPath, err := s.objectPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	content, err := os.Open(objectPath)
+	if err != nil {
+		return nil, nil, handleLocalError(err)
+	}
+
+	attrs, err := s.attributes(path, content)
+	if err != nil {
+		content.Close()
+
+		return nil, nil, err
+	}
+
+	return content, attrs, nil
+}
+
+func (s *localStore) statObject(path string) (*objectAttributes, error) {
+	content, attrs, err := s.getObject(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := content.Close(); err != nil {
+		return nil, err
+	}
+
+	return attrs, nil
+}
+
+func (s *localStore) listObjects(prefix string, recursive bool) ([]string, error) {
+	startPath := s.rootPath
+
+	if index := strings.LastIndex(prefix, "/"); index > 0 {
+		folder := filepath.FromSlash(prefix[:index])
+
+		// folders outside of the root path are not listed
+		if !filepath.IsLocal(folder) {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidPath, prefix)
+		}
+
+		startPath = filepath.Join(s.rootPath, folder)
+	}
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	keys := make([]string, 0)
+
+	err := filepath.WalkDir(startPath, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() {
+			if filePath == filepath.Join(s.rootPath, localMetaDataFolder) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		relPath, err := filepath.Rel(s.rootPath, filePath)
+		if err != nil {
+			return err
+		}
+
+		key := filepath.ToSlash(relPath)
+
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		if !recursive && strings.Contains(strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/"), "/") {
+			return nil
+		}
+
+		keys = append(keys, key)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+func (s *localStore) removeObject(path string) error {
+	objectPath, err := s.objectPath(path)
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := os.Remove(objectPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	if err := os.Remove(s.metaDataPath(path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// s3 has no folders, so empty parent folders are removed as well.
+	removeEmptyFolders(filepath.Dir(objectPath), s.rootPath)
+	removeEmptyFolders(filepath.Dir(s.metaDataPath(path)), filepath.Join(s.rootPath, localMetaDataFolder, localObjectsFolder))
+
+	return nil
+}
+
+func (s *localStore) getLifecycle() (*lifecycle.Configuration, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	config := lifecycle.NewConfiguration()
+
+	data, err := os.ReadFile(filepath.Join(s.rootPath, localMetaDataFolder, localLifecycleFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := xml.Unmarshal(data, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+func (s *localStore) setLifecycle(config *lifecycle.Configuration) error {
+	data, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return os.WriteFile(filepath.Join(s.rootPath, localMetaDataFolder, localLifecycleFile), data, 0o600)
+}
+
+// setTags rewrites the sidecar file of the object with the given tags.
+func (s *localStore) setTags(path string, tags map[string]string) error {
+	objectPath, err := s.objectPath(path)
+	if err != nil {
+		return err
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	content, err := os.Open(objectPath)
+	if err != nil {
+		return handleLocalError(err)
+	}
+
+	attrs, err := s.attributes(path, content)
+
+	content.Close()
+
+	if err != nil {
+		return err
+	}
+
+	metaData, err := json.Marshal(&localMetaData{
+		ContentType: attrs.contentType,
+		MetaData:    attrs.metaData,
+		Tags:        tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	tempMetaData, _, err := s.writeTempFile(bytes.NewReader(metaData))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tempMetaData)
+
+	return renameFile(tempMetaData, s.metaDataPath(path))
+}
+
+// objectLink returns a link to the local file. Files are opened directly, so the options have no effect.
+func (s *localStore) objectLink(path string, _ time.Duration, _ []FileLinkOption) (*url.URL, error) {
+	objectPath, err := s.objectPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	linkPath := filepath.ToSlash(objectPath)
+
+	if !strings.HasPrefix(linkPath, "/") {
+		linkPath = "/" + linkPath // windows drive letters
+	}
+
+	return &url.URL{Scheme: localLinkScheme, Path: linkPath}, nil
+}
+
+// objectPath returns the local file path of the object under the given s3 path.
+func (s *localStore) objectPath(path string) (string, error) {
+	localPath := filepath.FromSlash(path)
+
+	if !filepath.IsLocal(localPath) || strings.HasSuffix(path, "/") {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidPath, path)
+	}
+
+	if folder, _, _ := strings.Cut(path, "/"); folder == localMetaDataFolder {
+		return "", fmt.Errorf("%w: '%s'", ErrInvalidPath, path)
+	}
+
+	return filepath.Join(s.rootPath, localPath), nil
+}
+
+// metaDataPath returns the local file path of the sidecar file of the object under the given s3 path.
+func (s *localStore) metaDataPath(path string) string {
+	return filepath.Join(s.rootPath, localMetaDataFolder, localObjectsFolder, filepath.FromSlash(path))
+}
+
+// attributes returns the attributes of the opened object.
+// Objects without a sidecar file, e.g. copied into the folder by hand, get a content type guessed by their extension.
+func (s *localStore) attributes(path string, content *os.File) (*objectAttributes, error) {
+	stat, err := content.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if stat.IsDir() {
+		return nil, ErrNotFound
+	}
+
+	metaData := new(localMetaData)
+
+	data, err := os.ReadFile(s.metaDataPath(path))
+
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, metaData); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, fs.ErrNotExist):
+		metaData.ContentType = mime.TypeByExtension(filepath.Ext(path))
+	default:
+		return nil, err
+	}
+
+	if metaData.ContentType == "" {
+		metaData.ContentType = defaultContentType
+	}
+
+	if metaData.MetaData == nil {
+		metaData.MetaData = make(map[string]string)
+	}
+
+	attrs := &objectAttributes{
+		size:         stat.Size(),
+		contentType:  metaData.ContentType,
+		metaData:     metaData.MetaData,
+		tags:         metaData.Tags,
+		modifiedDate: stat.ModTime().UTC(),
+	}
+
+	return attrs, nil
+}
+
+// writeTempFile writes the content into a new temporary file and returns its path and size.
+func (s *localStore) writeTempFile(content io.Reader) (string, int64, error) {
+	tempFile, err := os.CreateTemp(filepath.Join(s.rootPath, localMetaDataFolder, localTempFolder), localTempFilePattern)
+	if err != nil {
+		return "", 0, err
+	}
+
+	size, err := io.Copy(tempFile, content)
+	if err != nil {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+
+		return "", 0, err
+	}
+
+	if err := tempFile.Close(); err != nil {
+		os.Remove(tempFile.Name())
+
+		return "", 0, err
+	}
+
+	return tempFile.Name(), size, nil
+}
+
+func renameFile(oldPath, newPath string) error {
+	if err := os.MkdirAll(filepath.Dir(newPath), 0o700); err != nil {
+		return err
+	}
+
+	return os.Rename(oldPath, newPath)
+}
+
+// removeEmptyFolders removes the given folder and its parents up to, but excluding, the stop folder as long as they are empty.
+func removeEmptyFolders(folder, stopFolder string) {
+	for folder != stopFolder && strings.HasPrefix(folder, stopFolder) {
+		if err := os.Remove(folder); err != nil {
+			return
+		}
+
+		folder = filepath.Dir(folder)
+	}
+}
+
+func handleLocalError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, syscall.ENOTDIR) {
+		return ErrNotFound
+	}
+
+	return err
+}
@@ -0,0 +1,122 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	codeNoObjectLockConfig = "NoSuchObjectLockConfiguration"
+	codeNoBucketLockConfig = "ObjectLockConfigurationNotFoundError"
+
+	headerLockMode        = "X-Amz-Object-Lock-Mode"
+	headerLockRetainUntil = "X-Amz-Object-Lock-Retain-Until-Date"
+	headerLockLegalHold   = "X-Amz-Object-Lock-Legal-Hold"
+)
+
+// RetentionMode is the object lock mode protecting a file from being deleted or overwritten.
+type RetentionMode string
+
+const (
+	// RetentionGovernance allows users with the s3:BypassGovernanceRetention permission to remove the lock.
+	RetentionGovernance RetentionMode = "GOVERNANCE"
+	// RetentionCompliance prevents everyone, including the root user, from removing the lock until it expires.
+	RetentionCompliance RetentionMode = "COMPLIANCE"
+)
+
+// Retention protects a version of a file from being deleted or overwritten until the retain until date.
+type Retention struct {
+	Mode            RetentionMode
+	RetainUntilDate time.Time
+}
+
+// DefaultRetention is the retention applied to all new files of a bucket, unless the upload sets a retention.
+// Either Days or Years is set.
+type DefaultRetention struct {
+	Mode  RetentionMode
+	Days  int
+	Years int
+}
+
+// ObjectLockConfig is the object lock configuration of a bucket.
+type ObjectLockConfig struct {
+	// Enabled reports whether the bucket has been created with object lock enabled.
+	Enabled bool
+	// DefaultRetention is nil if the bucket has no default retention.
+	DefaultRetention *DefaultRetention
+}
+
+// LockState is the object lock state of a version of a file.
+type LockState struct {
+	// Retention is nil if the file has no retention.
+	Retention *Retention
+	LegalHold bool
+}
+
+// objectLockState returns the lock state of the object from its response headers, or nil if the object is not locked.
+func objectLockState(headers http.Header) *LockState {
+	state := &LockState{
+		LegalHold: headers.Get(headerLockLegalHold) == string(minio.LegalHoldEnabled),
+	}
+
+	if mode := headers.Get(headerLockMode); mode != "" {
+		retainUntil, _ := time.Parse(time.RFC3339, headers.Get(headerLockRetainUntil)) //nolint:errcheck // zero if missing
+
+		state.Retention = &Retention{
+			Mode:            RetentionMode(mode),
+			RetainUntilDate: retainUntil,
+		}
+	}
+
+	if state.Retention == nil && !state.LegalHold {
+		return nil
+	}
+
+	return state
+}
+
+// validate rejects unknown retention modes and retentions without retain until date.
+func (r *Retention) validate() error {
+	if !minio.RetentionMode(r.Mode).IsValid() || r.RetainUntilDate.IsZero() {
+		return ErrInvalidRetention
+	}
+
+	return nil
+}
+
+func legalHoldStatus(enabled bool) minio.LegalHoldStatus {
+	if enabled {
+		return minio.LegalHoldEnabled
+	}
+
+	return minio.LegalHoldDisabled
+}
+
+// applyLock sets the retention and legal hold of the upload options on the client options.
+func (o *uploadOptions) applyLock() error {
+	if o.retention != nil {
+		if err := o.retention.validate(); err != nil {
+			return err
+		}
+
+		o.clientOptions.Mode = minio.RetentionMode(o.retention.Mode)
+		o.clientOptions.RetainUntilDate = o.retention.RetainUntilDate
+	}
+
+	if o.legalHold {
+		o.clientOptions.LegalHold = minio.LegalHoldEnabled
+	}
+
+	return nil
+}
+
+// isNoLockConfig reports whether the error occurred because the object or bucket has no lock configuration.
+func isNoLockConfig(err error) bool {
+	var minioResponse minio.ErrorResponse
+
+	return errors.As(err, &minioResponse) &&
+		(minioResponse.Code == codeNoObjectLockConfig || minioResponse.Code == codeNoBucketLockConfig)
+}
@@ -0,0 +1,289 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ObjectLock(t *testing.T) {
+	t.Parallel()
+
+	const lockedBucketName = "test-object-lock"
+
+	ctx := context.Background()
+
+	err := minioClient.MakeBucket(ctx, lockedBucketName, minio.MakeBucketOptions{ObjectLocking: true})
+	require.NoError(t, err)
+
+	mode := minio.Governance
+	validity := uint(1)
+	unit := minio.Days
+
+	err = minioClient.SetObjectLockConfig(ctx, lockedBucketName, &mode, &validity, &unit)
+	require.NoError(t, err)
+
+	clientDetails := &s3.ClientDetails{
+		Host:         s3URL,
+		AccessKey:    s3User,
+		AccessSecret: s3Pwd,
+		BucketName:   lockedBucketName,
+	}
+
+	s3Client, err := s3.NewClient(clientDetails)
+	require.NoError(t, err)
+
+	t.Cleanup(s3Client.Close)
+
+	t.Run("object lock config", func(t *testing.T) {
+		t.Parallel()
+
+		config, err := s3Client.GetObjectLockConfig(ctx)
+		require.NoError(t, err)
+		require.Equal(t, &s3.ObjectLockConfig{
+			Enabled:          true,
+			DefaultRetention: &s3.DefaultRetention{Mode: s3.RetentionGovernance, Days: 1},
+		}, config)
+
+		config, err = getS3Client(t).GetObjectLockConfig(ctx)
+		require.NoError(t, err)
+		require.Equal(t, &s3.ObjectLockConfig{}, config)
+	})
+
+	t.Run("upload with retention and legal hold", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := "test-object-lock/" + uuid.NewString()
+		retainUntilDate := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		uploaded, err := s3Client.UploadFile(ctx,
+			s3.NewUpload(strings.NewReader("locked"), nil, filePath, contentType, nil),
+			s3.WithRetention(s3.RetentionGovernance, retainUntilDate),
+			s3.WithLegalHold(),
+		)
+		require.NoError(t, err)
+
+		// the lock of the upload must not apply to the version without checksums
+		requireLockedVersion(t, s3Client, filePath, uploaded.VersionID)
+
+		info, err := s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.NotNil(t, info.Lock)
+		require.True(t, info.Lock.LegalHold)
+		require.Equal(t, s3.RetentionGovernance, info.Lock.Retention.Mode)
+		require.WithinDuration(t, retainUntilDate, info.Lock.Retention.RetainUntilDate, time.Second)
+
+		retention, err := s3Client.GetRetention(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, s3.RetentionGovernance, retention.Mode)
+		require.WithinDuration(t, retainUntilDate, retention.RetainUntilDate, time.Second)
+
+		legalHold, err := s3Client.GetLegalHold(ctx, filePath, s3.WithLockVersionID(uploaded.VersionID))
+		require.NoError(t, err)
+		require.True(t, legalHold)
+
+		err = s3Client.RemoveFile(ctx, filePath, s3.WithRemoveVersionID(uploaded.VersionID))
+		require.ErrorIs(t, err, s3.ErrObjectLocked)
+
+		err = s3Client.SetLegalHold(ctx, filePath, false)
+		require.NoError(t, err)
+
+		err = s3Client.SetRetention(ctx, filePath, nil)
+		require.ErrorIs(t, err, s3.ErrObjectLocked)
+
+		err = s3Client.SetRetention(ctx, filePath, nil, s3.WithGovernanceBypass())
+		require.NoError(t, err)
+
+		retention, err = s3Client.GetRetention(ctx, filePath)
+		require.NoError(t, err)
+		require.Nil(t, retention)
+
+		info, err = s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.Nil(t, info.Lock)
+
+		err = s3Client.RemoveFile(ctx, filePath, s3.WithRemoveVersionID(uploaded.VersionID))
+		require.NoError(t, err)
+	})
+
+	t.Run("upload stream with retention and legal hold", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := "test-object-lock/" + uuid.NewString()
+		retainUntilDate := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+		uploaded, err := s3Client.UploadStream(ctx,
+			s3.NewStreamUpload(strings.NewReader("locked stream"), filePath, contentType, nil),
+			s3.WithRetention(s3.RetentionGovernance, retainUntilDate),
+			s3.WithLegalHold(),
+		)
+		require.NoError(t, err)
+
+		// the lock of the upload only applies to the copy recording the checksums
+		requireLockedVersion(t, s3Client, filePath, uploaded.VersionID)
+
+		info, err := s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.NotNil(t, info.Lock)
+		require.True(t, info.Lock.LegalHold)
+		require.Equal(t, s3.RetentionGovernance, info.Lock.Retention.Mode)
+
+		err = s3Client.SetLegalHold(ctx, filePath, false)
+		require.NoError(t, err)
+
+		err = s3Client.RemoveFile(ctx, filePath,
+			s3.WithClientRemoveOptions(s3.ClientRemoveOptions{GovernanceBypass: true}),
+			s3.WithRemoveVersionID(uploaded.VersionID),
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("upload with default compliance retention", func(t *testing.T) {
+		t.Parallel()
+
+		const complianceBucketName = "test-object-lock-compliance"
+
+		err := minioClient.MakeBucket(ctx, complianceBucketName, minio.MakeBucketOptions{ObjectLocking: true})
+		require.NoError(t, err)
+
+		mode := minio.Compliance
+
+		err = minioClient.SetObjectLockConfig(ctx, complianceBucketName, &mode, &validity, &unit)
+		require.NoError(t, err)
+
+		complianceClient, err := s3.NewClient(&s3.ClientDetails{
+			Host:         s3URL,
+			AccessKey:    s3User,
+			AccessSecret: s3Pwd,
+			BucketName:   complianceBucketName,
+		})
+		require.NoError(t, err)
+
+		t.Cleanup(complianceClient.Close)
+
+		filePath := "test-object-lock/" + uuid.NewString()
+		content := "locked by default"
+
+		expectedChecksum, err := s3.GenerateCheckSumCRC32C(strings.NewReader(content))
+		require.NoError(t, err)
+
+		// the version without checksums cannot be removed, which does not fail the upload
+		uploaded, err := complianceClient.UploadStream(ctx, s3.NewStreamUpload(strings.NewReader(content), filePath, contentType, nil))
+		require.NoError(t, err)
+		require.Equal(t, expectedChecksum, uploaded.ChecksumCRC32C)
+
+		versions := 0
+
+		for version, err := range complianceClient.ListFileVersions(ctx, filePath) {
+			require.NoError(t, err)
+			require.Equal(t, version.VersionID == uploaded.VersionID, version.IsLatest)
+
+			versions++
+		}
+
+		require.Equal(t, 2, versions)
+
+		info, err := complianceClient.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, expectedChecksum, info.ChecksumCRC32C)
+	})
+
+	t.Run("set retention", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := "test-object-lock/" + uuid.NewString()
+
+		_, err := s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader("locked later"), nil, filePath, contentType, nil))
+		require.NoError(t, err)
+
+		retention := &s3.Retention{
+			Mode:            s3.RetentionGovernance,
+			RetainUntilDate: time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second),
+		}
+
+		err = s3Client.SetRetention(ctx, filePath, retention)
+		require.NoError(t, err)
+
+		stored, err := s3Client.GetRetention(ctx, filePath)
+		require.NoError(t, err)
+		require.Equal(t, retention.Mode, stored.Mode)
+		require.WithinDuration(t, retention.RetainUntilDate, stored.RetainUntilDate, time.Second)
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3Client.UploadFile(ctx,
+			s3.NewUpload(strings.NewReader("locked"), nil, "test-object-lock/"+uuid.NewString(), contentType, nil),
+			s3.WithRetention("UNKNOWN", time.Now().Add(time.Hour)),
+		)
+		require.ErrorIs(t, err, s3.ErrInvalidRetention)
+
+		err = s3Client.SetRetention(ctx, "test-object-lock/file", &s3.Retention{Mode: s3.RetentionCompliance})
+		require.ErrorIs(t, err, s3.ErrInvalidRetention)
+	})
+}
+
+func testStoreObjectLock(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	_, err := s3Client.UploadFile(ctx,
+		s3.NewUpload(strings.NewReader("locked"), nil, "test-store-object-lock/"+uuid.NewString(), contentType, nil),
+		s3.WithLegalHold(),
+	)
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	uploaded := uploadTestFileWithClient(t, s3Client, "test-store-object-lock", testFile1Name)
+
+	retention, err := s3Client.GetRetention(ctx, uploaded.filePath)
+	require.NoError(t, err)
+	require.Nil(t, retention)
+
+	legalHold, err := s3Client.GetLegalHold(ctx, uploaded.filePath)
+	require.NoError(t, err)
+	require.False(t, legalHold)
+
+	_, err = s3Client.GetRetention(ctx, uploaded.filePath+"-missing")
+	require.ErrorIs(t, err, s3.ErrNotFound)
+
+	err = s3Client.SetLegalHold(ctx, uploaded.filePath, true)
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	config, err := s3Client.GetObjectLockConfig(ctx)
+	require.NoError(t, err)
+	require.False(t, config.Enabled)
+}
+
+// requireLockedVersion requires the version to be the latest version of the file and the only version locked by
+// the upload. Other versions are only left behind by the default retention of the bucket.
+func requireLockedVersion(t *testing.T, s3Client s3.Client, filePath, versionID string) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	for version, err := range s3Client.ListFileVersions(ctx, filePath) {
+		require.NoError(t, err)
+
+		if version.Path != filePath {
+			continue
+		}
+
+		require.Equal(t, version.VersionID == versionID, version.IsLatest)
+
+		if version.VersionID == versionID {
+			continue
+		}
+
+		legalHold, err := s3Client.GetLegalHold(ctx, filePath, s3.WithLockVersionID(version.VersionID))
+		require.NoError(t, err)
+		require.False(t, legalHold)
+	}
+}
@@ -0,0 +1,192 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+const memoryLinkScheme = "memory"
+
+type memoryStore struct {
+	bucketName string
+	urlValues  url.Values
+	mtx        sync.RWMutex
+	objects    map[string]*memoryObject
+	lifecycle  *lifecycle.Configuration
+}
+
+type memoryObject struct {
+	content []byte
+	attrs   objectAttributes
+}
+
+// NewMemoryClient instantiates a s3 client which keeps all objects in memory.
+// The client is safe for concurrent use and honours the integrity client options.
+//
+// Notes: Only meant to be used for testing purposes. No docker engine is required.
+func NewMemoryClient(bucketName string, options ...ClientOption) (Client, error) {
+	const errMessage = "failed to create memory s3 client: %w"
+
+	if bucketName == "" {
+		return nil, fmt.Errorf(errMessage, ErrEmptyBucketName)
+	}
+
+	store := &memoryStore{
+		bucketName: bucketName,
+		urlValues:  make(url.Values),
+		objects:    make(map[string]*memoryObject),
+	}
+
+	store.urlValues.Set("response-content-disposition", "inline")
+
+	client, err := newStoreClient(store, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return client, nil
+}
+
+func (s *memoryStore) putObject(path string, content io.Reader, attrs func() *objectAttributes) (int64, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return 0, err
+	}
+
+	obj := &memoryObject{
+		content: data,
+		attrs:   *attrs(),
+	}
+
+	obj.attrs.size = int64(len(data))
+
+	s.mtx.Lock()
+	s.objects[path] = obj
+	s.mtx.Unlock()
+
+	return obj.attrs.size, nil
+}
+
+func (s *memoryStore) getObject(path string) (io.ReadSeekCloser, *objectAttributes, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return nil, nil, ErrNotFound
+	}
+
+	attrs := obj.attrs
+
+	return nopSeekCloser{bytes.NewReader(obj.content)}, &attrs, nil
+}
+
+func (s *memoryStore) statObject(path string) (*objectAttributes, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	attrs := obj.attrs
+
+	return &attrs, nil
+}
+
+func (s *memoryStore) listObjects(prefix string, recursive bool) ([]string, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	keys := make([]string, 0)
+
+	for key := range s.objects {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		if !recursive && strings.Contains(strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/"), "/") {
+			continue
+		}
+
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys, nil
+}
+
+func (s *memoryStore) removeObject(path string) error {
+	s.mtx.Lock()
+	delete(s.objects, path)
+	s.mtx.Unlock()
+
+	return nil
+}
+
+func (s *memoryStore) getLifecycle() (*lifecycle.Configuration, error) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	config := lifecycle.NewConfiguration()
+
+	if s.lifecycle != nil {
+		config.Rules = slices.Clone(s.lifecycle.Rules)
+	}
+
+	return config, nil
+}
+
+func (s *memoryStore) setLifecycle(config *lifecycle.Configuration) error {
+	s.mtx.Lock()
+	s.lifecycle = config
+	s.mtx.Unlock()
+
+	return nil
+}
+
+func (s *memoryStore) setTags(path string, tags map[string]string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	obj, ok := s.objects[path]
+	if !ok {
+		return ErrNotFound
+	}
+
+	obj.attrs.tags = tags
+
+	return nil
+}
+
+func (s *memoryStore) objectLink(path string, expiration time.Duration, options []FileLinkOption) (*url.URL, error) {
+	query := fileLinkValues(s.urlValues, options)
+	query.Set("X-Amz-Expires", strconv.FormatInt(int64(expiration/time.Second), 10))
+
+	link := &url.URL{
+		Scheme:   memoryLinkScheme,
+		Path:     "/" + s.bucketName + "/" + path,
+		RawQuery: query.Encode(),
+	}
+
+	return link, nil
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error {
+	return nil
+}
@@ -1,102 +1,383 @@
 package s3 //nolint:revive // package name matches folder name
 
 import (
+	"cmp"
 	"context"
-	"encoding/xml"
+	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"maps"
+	"net/http"
 	"net/url"
 	pathpkg "path"
+	"slices"
+	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/tags"
 )
 
 const (
-	defaultUploadSize int64 = -1
+	defaultUploadSize  int64 = -1
+	maxSingleCopySize  int64 = 5 << 30  // 5 GiB
+	compressedPartSize       = 16 << 20 // 16 MiB
+	maxRemoveBatchSize       = 1000
+	headerStorageClass       = "X-Amz-Storage-Class"
 )
 
 func (c *client) UploadFile(ctx context.Context, upload *Upload, options ...UploadOption) (*UploadInfo, error) {
 	const errMessage = "failed to upload file: %w"
 
+	size := defaultUploadSize
+	uploadSize := upload.Size
+
+	if uploadSize != nil {
+		size = *uploadSize
+	}
+
+	stream := NewStreamUpload(upload.ReadSeeker, upload.Path, upload.ContentType, upload.MetaData)
+	stream.Tags = upload.Tags
+
+	// every attempt uploads the content from the start
+	info, err := retry(ctx, c.retryPolicy, OperationUploadFile, upload.Path, func(ctx context.Context) (*UploadInfo, error) {
+		if c.integrityEnabled() || c.retryPolicy != nil {
+			if _, err := upload.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+
+		return c.putObject(ctx, stream, size, options...)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *client) UploadStream(ctx context.Context, upload *StreamUpload, options ...UploadOption) (*UploadInfo, error) {
+	const errMessage = "failed to upload stream: %w"
+
+	info, err := c.putObject(ctx, upload, defaultUploadSize, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+// putObject uploads the data of the given size. A size of -1 uploads data of unknown size using a multipart upload.
+func (c *client) putObject(ctx context.Context, upload *StreamUpload, size int64, options ...UploadOption) (*UploadInfo, error) {
 	opts := new(uploadOptions)
 
 	for i := range options {
 		options[i](opts)
 	}
 
-	size := defaultUploadSize
-	uploadSize := upload.Size
+	if err := opts.applyLock(); err != nil {
+		return nil, err
+	}
 
-	if uploadSize != nil {
-		size = *uploadSize
+	if opts.encryption != nil || opts.clientOptions.ServerSideEncryption == nil {
+		opts.clientOptions.ServerSideEncryption = c.serverSide(opts.encryption)
 	}
 
 	if opts.clientOptions.UserMetadata == nil {
 		opts.clientOptions.UserMetadata = make(map[string]string)
 	}
 
-	var (
-		crc32c string
-		md5    string
-	)
+	maps.Copy(opts.clientOptions.UserMetadata, upload.MetaData)
 
-	if c.useIntegrityCRC32C {
-		checksum, err := getCheckSumCRC32C(upload)
-		if err != nil {
-			return nil, fmt.Errorf(errMessage, err)
+	if len(upload.Tags) > 0 {
+		if _, err := objectTags(upload.Tags); err != nil {
+			return nil, err
 		}
 
-		crc32c = checksum.hex()
+		opts.clientOptions.UserTags = upload.Tags
+	}
+
+	contentType := upload.ContentType
+
+	if contentType != "" {
+		opts.clientOptions.ContentType = contentType
+	}
+
+	// s3 computes the native checksums of the stored content, so they are not used for encrypted or compressed files
+	transformed := c.keyProvider != nil || opts.compression != CompressionNone
+
+	if _, native := c.nativeAlgorithm(); native != nil && !transformed {
+		opts.clientOptions.Checksum = native.nativeType
+	}
+
+	content := upload.Reader
+
+	hasher, err := c.newIntegrityHasher()
+	if err != nil {
+		return nil, err
+	}
 
-		opts.clientOptions.UserMetadata[keyCR32CChecksum] = crc32c
+	if c.integrityEnabled() {
+		// the checksums are computed while the data streams to s3
+		content = io.TeeReader(content, hasher)
 	}
 
-	if c.useIntegrityMD5 {
-		checksum, err := getCheckSumMD5(upload)
+	var compressor *compressingReader
+
+	if opts.compression != CompressionNone {
+		compressor, err = newCompressingReader(content, opts.compression)
 		if err != nil {
-			return nil, fmt.Errorf(errMessage, err)
+			return nil, err
 		}
 
-		md5 = checksum.hex()
+		content = compressor
+		size = defaultUploadSize
+
+		opts.clientOptions.UserMetadata[keyCompression] = string(opts.compression)
 
-		opts.clientOptions.UserMetadata[keyMD5Checksum] = md5
+		// the size of the compressed content is unknown, so the parts are not sized for the largest possible file
+		if opts.clientOptions.PartSize == 0 {
+			opts.clientOptions.PartSize = compressedPartSize
+		}
 	}
 
-	maps.Copy(opts.clientOptions.UserMetadata, upload.MetaData)
+	if c.keyProvider != nil {
+		var envelope map[string]string
 
-	contentType := upload.ContentType
+		content, envelope, err = sealEnvelope(ctx, c.keyProvider, upload.Path, content)
+		if err != nil {
+			return nil, err
+		}
 
-	if contentType != "" {
-		opts.clientOptions.ContentType = contentType
+		maps.Copy(opts.clientOptions.UserMetadata, envelope)
+
+		size = encryptedSize(size)
 	}
 
-	objInfo, err := c.minioClient.PutObject(
-		ctx,
-		c.bucketName,
-		upload.Path,
-		upload,
-		size,
-		minio.PutObjectOptions(opts.clientOptions),
-	)
+	putOptions := minio.PutObjectOptions(opts.clientOptions)
+
+	// uploads may be copied to record their checksums, so only the final version is locked
+	putOptions.Mode = ""
+	putOptions.RetainUntilDate = time.Time{}
+	putOptions.LegalHold = ""
+
+	objInfo, err := c.minioClient.PutObject(ctx, c.bucketName, upload.Path, content, size, putOptions)
 	if err != nil {
-		return nil, fmt.Errorf(errMessage, err)
+		return nil, handleClientError(err)
+	}
+
+	uploadedSize := objInfo.Size
+
+	switch {
+	case compressor != nil:
+		uploadedSize = compressor.size
+	case c.keyProvider != nil:
+		uploadedSize = plaintextSize(objInfo.Size, envelopeChunkSize)
+	}
+
+	integrity := hasher.integrity()
+	versionID := objInfo.VersionID
+	native := uploadNativeIntegrity(&objInfo)
+
+	if transformed {
+		native = Integrity{}
+	}
+
+	metaData := c.integrityMetaData(integrity, native)
+
+	if compressor != nil {
+		// the size of the uncompressed content is only known once the upload completed
+		metaData[keyCompressionSize] = strconv.FormatInt(compressor.size, 10)
+	}
+
+	if len(metaData) > 0 {
+		maps.Copy(opts.clientOptions.UserMetadata, metaData)
+
+		versionID, err = c.recordIntegrity(ctx, &objInfo, &opts.clientOptions)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := c.lockVersion(ctx, upload.Path, versionID, &opts.clientOptions); err != nil {
+		return nil, err
 	}
 
 	info := &UploadInfo{
-		Size: objInfo.Size,
-		Integrity: Integrity{
-			ChecksumCRC32C: crc32c,
-			ChecksumMD5:    md5,
-		},
+		Size:      uploadedSize,
+		VersionID: versionID,
+		Integrity: integrity,
 	}
 
 	return info, nil
 }
 
+// recordIntegrity stores the checksums and the size of compressed content in the metadata of the uploaded stream and
+// returns the version of the object. Since they are only known once the upload completed, the metadata is replaced
+// using a server-side copy.
+func (c *client) recordIntegrity(ctx context.Context, objInfo *minio.UploadInfo, opts *ClientUploadOptions) (string, error) {
+	const errMessage = "failed to record checksums: %w"
+
+	metaData := maps.Clone(opts.UserMetadata)
+
+	if opts.StorageClass != "" {
+		metaData[headerStorageClass] = opts.StorageClass
+	}
+
+	contentType := opts.ContentType
+
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket:             c.bucketName,
+		Object:             objInfo.Key,
+		Encryption:         opts.ServerSideEncryption,
+		UserMetadata:       metaData,
+		ReplaceMetadata:    true,
+		LegalHold:          opts.LegalHold,
+		Mode:               opts.Mode,
+		RetainUntilDate:    opts.RetainUntilDate,
+		Expires:            opts.Expires,
+		ContentType:        contentType,
+		ContentEncoding:    opts.ContentEncoding,
+		ContentDisposition: opts.ContentDisposition,
+		ContentLanguage:    opts.ContentLanguage,
+		CacheControl:       opts.CacheControl,
+		ChecksumType:       opts.Checksum,
+	}
+
+	src := minio.CopySrcOptions{
+		Bucket:     c.bucketName,
+		Object:     objInfo.Key,
+		VersionID:  objInfo.VersionID,
+		MatchETag:  objInfo.ETag,
+		Encryption: customerKey(opts.ServerSideEncryption),
+	}
+
+	copied, err := c.copyObject(ctx, dst, src, objInfo.Size)
+	if err != nil {
+		return "", fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	// in a versioned bucket the copy is a new version, so the version without checksums is removed. It is not locked
+	// by the upload, but may be locked by the default retention of the bucket, which only leaves it behind.
+	if objInfo.VersionID != "" && copied.VersionID != objInfo.VersionID {
+		_ = c.minioClient.RemoveObject(ctx, c.bucketName, objInfo.Key, minio.RemoveObjectOptions{VersionID: objInfo.VersionID})
+	}
+
+	return copied.VersionID, nil
+}
+
+// lockVersion applies the retention and legal hold of the upload options to the uploaded version.
+func (c *client) lockVersion(ctx context.Context, path, versionID string, opts *ClientUploadOptions) error {
+	const errMessage = "failed to lock file: %w"
+
+	if opts.Mode != "" {
+		retentionOptions := minio.PutObjectRetentionOptions{
+			Mode:            &opts.Mode,
+			RetainUntilDate: &opts.RetainUntilDate,
+			VersionID:       versionID,
+		}
+
+		if err := c.minioClient.PutObjectRetention(ctx, c.bucketName, path, retentionOptions); err != nil {
+			return fmt.Errorf(errMessage, handleClientError(err))
+		}
+	}
+
+	if opts.LegalHold != "" {
+		legalHoldOptions := minio.PutObjectLegalHoldOptions{
+			VersionID: versionID,
+			Status:    &opts.LegalHold,
+		}
+
+		if err := c.minioClient.PutObjectLegalHold(ctx, c.bucketName, path, legalHoldOptions); err != nil {
+			return fmt.Errorf(errMessage, handleClientError(err))
+		}
+	}
+
+	return nil
+}
+
+// copyObject copies an object server-side. Objects larger than 5 GiB are copied using a multipart copy.
+func (c *client) copyObject(ctx context.Context, dst minio.CopyDestOptions, src minio.CopySrcOptions, size int64) (minio.UploadInfo, error) {
+	if size > maxSingleCopySize {
+		// the multipart copy only applies the metadata to the new object, so the content headers are passed along
+		metaData := make(map[string]string, len(dst.UserMetadata))
+
+		maps.Copy(metaData, dst.UserMetadata)
+
+		for header, value := range map[string]string{
+			"Content-Type":        dst.ContentType,
+			"Content-Encoding":    dst.ContentEncoding,
+			"Content-Disposition": dst.ContentDisposition,
+			"Content-Language":    dst.ContentLanguage,
+			"Cache-Control":       dst.CacheControl,
+		} {
+			if value != "" {
+				metaData[header] = value
+			}
+		}
+
+		dst.UserMetadata = metaData
+
+		return c.minioClient.ComposeObject(ctx, dst, src)
+	}
+
+	return c.minioClient.CopyObject(ctx, dst, src)
+}
+
+// resealObject copies a client-side encrypted object to another path. The object is downloaded, decrypted and
+// uploaded encrypted for the destination path, since its content is bound to the path of the source.
+func (c *client) resealObject(
+	ctx context.Context,
+	dst minio.CopyDestOptions,
+	src minio.CopySrcOptions,
+	env *envelope,
+	size int64,
+) (minio.UploadInfo, error) {
+	getOptions := minio.GetObjectOptions{
+		VersionID:            src.VersionID,
+		ServerSideEncryption: src.Encryption,
+	}
+
+	if err := getOptions.SetMatchETag(src.MatchETag); err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	object, err := c.minioClient.GetObject(ctx, src.Bucket, src.Object, getOptions)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	defer object.Close()
+
+	content, envelope, err := env.reseal(ctx, c.keyProvider, src.Object, dst.Object, object)
+	if err != nil {
+		return minio.UploadInfo{}, err
+	}
+
+	metaData := maps.Clone(dst.UserMetadata)
+
+	maps.Copy(metaData, envelope)
+
+	putOptions := minio.PutObjectOptions{
+		UserMetadata:         metaData,
+		ContentType:          dst.ContentType,
+		ContentEncoding:      dst.ContentEncoding,
+		ContentDisposition:   dst.ContentDisposition,
+		ContentLanguage:      dst.ContentLanguage,
+		CacheControl:         dst.CacheControl,
+		ServerSideEncryption: dst.Encryption,
+	}
+
+	return c.minioClient.PutObject(
+		ctx, dst.Bucket, dst.Object, content, encryptedSize(plaintextSize(size, env.chunkSize)), putOptions,
+	)
+}
+
 func (c *client) GetFile(ctx context.Context, path string, options ...GetOption) (File, error) {
 	const errMessage = "failed to get file from s3: %w"
 
@@ -106,18 +387,40 @@ func (c *client) GetFile(ctx context.Context, path string, options ...GetOption)
 		options[i](opts)
 	}
 
+	opts.clientOptions.Checksum = true
+
+	if opts.versionID != "" {
+		opts.clientOptions.VersionID = opts.versionID
+	}
+
+	if opts.encryption != nil || opts.clientOptions.ServerSideEncryption == nil {
+		opts.clientOptions.ServerSideEncryption = customerKey(c.serverSide(opts.encryption))
+	}
+
+	file, err := retryOpen(ctx, c.retryPolicy, OperationGetFile, path, func(ctx context.Context, cancel func()) (File, error) {
+		return c.getFile(ctx, cancel, path, opts)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return file, nil
+}
+
+// getFile opens the file once. The context of the request is released by cancel once the file is closed.
+func (c *client) getFile(ctx context.Context, cancel func(), path string, opts *getOptions) (File, error) {
 	object, err := c.minioClient.GetObject(ctx, c.bucketName, path, minio.GetObjectOptions(opts.clientOptions))
 	if err != nil {
-		return nil, fmt.Errorf(errMessage, handleClientError(err))
+		return nil, handleClientError(err)
 	}
 
 	objInfo, err := object.Stat()
 	if err != nil {
-		return nil, fmt.Errorf(errMessage, handleClientError(err))
+		return nil, handleClientError(err)
 	}
 
 	if objInfo.Err != nil {
-		return nil, fmt.Errorf(errMessage, objInfo.Err)
+		return nil, handleClientError(objInfo.Err)
 	}
 
 	info := &FileInfo{
@@ -127,25 +430,52 @@ func (c *client) GetFile(ctx context.Context, path string, options ...GetOption)
 		ContentType:  objInfo.ContentType,
 		MetaData:     objInfo.UserMetadata,
 		ModifiedDate: objInfo.LastModified,
+		VersionID:    objInfo.VersionID,
+		Lock:         objectLockState(objInfo.Metadata),
+		Encryption:   objectEncryption(objInfo.Metadata),
 	}
 
-	if err = c.handleIntegrity(object, info, opts); err != nil {
-		return nil, fmt.Errorf(errMessage, err)
+	content, native, err := openFile(ctx, c.keyProvider, object, info, objectNativeIntegrity(&objInfo))
+	if err != nil {
+		object.Close()
+
+		return nil, err
+	}
+
+	content, err = c.handleIntegrity(content, info, native, opts)
+	if err != nil {
+		object.Close()
+
+		return nil, err
 	}
 
-	return &file{ReadCloser: object, info: info}, nil
+	return &file{ReadCloser: &releasingReader{ReadCloser: content, release: cancel}, info: info}, nil
 }
 
-func (c *client) GetFileInfo(ctx context.Context, path string) (*FileInfo, error) {
+func (c *client) GetFileInfo(ctx context.Context, path string, options ...FileInfoOption) (*FileInfo, error) {
 	const errMessage = "failed to get file info: %w"
 
-	objInfo, err := c.minioClient.GetObjectACL(ctx, c.bucketName, path)
+	opts := new(fileInfoOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	statOptions := minio.StatObjectOptions{
+		VersionID:            opts.versionID,
+		Checksum:             true,
+		ServerSideEncryption: customerKey(c.serverSide(opts.encryption)),
+	}
+
+	objInfo, err := retry(ctx, c.retryPolicy, OperationGetFileInfo, path, func(ctx context.Context) (minio.ObjectInfo, error) {
+		return c.minioClient.StatObject(ctx, c.bucketName, path, statOptions)
+	})
 	if err != nil {
 		return nil, fmt.Errorf(errMessage, handleClientError(err))
 	}
 
 	if objInfo.Err != nil {
-		return nil, fmt.Errorf(errMessage, objInfo.Err)
+		return nil, fmt.Errorf(errMessage, handleClientError(objInfo.Err))
 	}
 
 	info := &FileInfo{
@@ -155,13 +485,49 @@ func (c *client) GetFileInfo(ctx context.Context, path string) (*FileInfo, error
 		ContentType:  objInfo.ContentType,
 		MetaData:     objInfo.UserMetadata,
 		ModifiedDate: objInfo.LastModified,
+		VersionID:    objInfo.VersionID,
+		Lock:         objectLockState(objInfo.Metadata),
+		Encryption:   objectEncryption(objInfo.Metadata),
+	}
+
+	native := objectNativeIntegrity(&objInfo)
+
+	stored, err := takeStoredContent(info)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
 	}
 
-	c.handleGetFileInfoIntegrity(info)
+	if stored.transformed() {
+		native = Integrity{}
+	}
+
+	c.handleGetFileInfoIntegrity(info, native)
 
 	return info, nil
 }
 
+// objectNativeIntegrity returns the checksums s3 stored in the standard checksum headers of the object.
+func objectNativeIntegrity(objInfo *minio.ObjectInfo) Integrity {
+	return nativeIntegrity(map[minio.ChecksumType]string{
+		minio.ChecksumCRC32:     objInfo.ChecksumCRC32,
+		minio.ChecksumCRC32C:    objInfo.ChecksumCRC32C,
+		minio.ChecksumSHA1:      objInfo.ChecksumSHA1,
+		minio.ChecksumSHA256:    objInfo.ChecksumSHA256,
+		minio.ChecksumCRC64NVME: objInfo.ChecksumCRC64NVME,
+	})
+}
+
+// uploadNativeIntegrity returns the checksums s3 stored in the standard checksum headers of the uploaded object.
+func uploadNativeIntegrity(objInfo *minio.UploadInfo) Integrity {
+	return nativeIntegrity(map[minio.ChecksumType]string{
+		minio.ChecksumCRC32:     objInfo.ChecksumCRC32,
+		minio.ChecksumCRC32C:    objInfo.ChecksumCRC32C,
+		minio.ChecksumSHA1:      objInfo.ChecksumSHA1,
+		minio.ChecksumSHA256:    objInfo.ChecksumSHA256,
+		minio.ChecksumCRC64NVME: objInfo.ChecksumCRC64NVME,
+	})
+}
+
 func (c *client) DownloadFile(ctx context.Context, path, localPath string, options ...DownloadOption) error {
 	const errMessage = "failed to download file: %w"
 
@@ -171,15 +537,32 @@ func (c *client) DownloadFile(ctx context.Context, path, localPath string, optio
 		options[i](opts)
 	}
 
-	err := c.minioClient.FGetObject(
-		ctx,
-		c.bucketName,
-		path,
-		localPath,
-		minio.GetObjectOptions(opts.clientOptions),
-	)
+	if opts.versionID != "" {
+		opts.clientOptions.VersionID = opts.versionID
+	}
+
+	if opts.encryption != nil || opts.clientOptions.ServerSideEncryption == nil {
+		opts.clientOptions.ServerSideEncryption = customerKey(c.serverSide(opts.encryption))
+	}
+
+	// the content is verified against the checksums stored with the file, so corrupted downloads are retried
+	getOpts := &getOptions{clientOptions: opts.clientOptions, autoVerify: true}
+	getOpts.clientOptions.Checksum = true
+
+	// the whole download is retried, since the content is written to the file system from the start again
+	err := retryCall(ctx, c.retryPolicy, OperationDownloadFile, path, func(ctx context.Context) error {
+		// encrypted and compressed files are decrypted and decompressed while they are written to the file system
+		file, err := c.getFile(ctx, func() {}, path, getOpts)
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		return writeLocalFile(localPath, file)
+	})
 	if err != nil {
-		return fmt.Errorf(errMessage, handleClientError(err))
+		return fmt.Errorf(errMessage, err)
 	}
 
 	return nil
@@ -188,200 +571,603 @@ func (c *client) DownloadFile(ctx context.Context, path, localPath string, optio
 func (c *client) GetDirectory(ctx context.Context, path string, options ...GetDirectoryOption) ([]File, error) {
 	const errMessage = "failed to get directory: %w"
 
-	getDirectoryOptions := new(getDirectoryOptions)
-
-	for i := range options {
-		options[i](getDirectoryOptions)
+	files, err := getDirectory(ctx, c.listKeys, c.concurrency, path, c.GetFile, options...)
+	if err != nil {
+		return files, fmt.Errorf(errMessage, err)
 	}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
-
-	objectCh := c.minioClient.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
-		Prefix:    path,
-		Recursive: true,
-	})
-
-	wg := new(sync.WaitGroup)
-	errCh := make(chan error)
-	mtx := new(sync.Mutex)
+	return files, nil
+}
 
-	result := make([]File, 0, len(objectCh))
+func (c *client) GetDirectoryInfos(ctx context.Context, path string, options ...GetDirectoryOption) ([]*FileInfo, error) {
+	const errMessage = "failed to get directory: %w"
 
-	for objInfo := range objectCh {
-		if objInfo.Err != nil {
-			return nil, fmt.Errorf(errMessage, objInfo.Err)
-		}
+	fileInfos, err := getDirectoryInfos(ctx, c.listKeys, c.concurrency, path, c.GetFileInfo, options...)
+	if err != nil {
+		return fileInfos, fmt.Errorf(errMessage, err)
+	}
 
-		wg.Add(1)
+	return fileInfos, nil
+}
 
-		go func(info minio.ObjectInfo) {
-			defer wg.Done()
+// listKeys returns an iterator over the keys of all objects under the given prefix.
+func (c *client) listKeys(ctx context.Context, prefix string, recursive bool) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		listing := c.listObjects(ctx, minio.ListObjectsOptions{
+			Prefix:    prefix,
+			Recursive: recursive,
+		})
 
-			doc, err := c.GetFile(
-				ctx,
-				info.Key,
-				[]GetOption{WithClientGetOptions(getDirectoryOptions.clientOptions)}...,
-			)
+		for objInfo, err := range listing {
 			if err != nil {
-				errCh <- err
+				yield("", handleClientError(err))
 
 				return
 			}
 
-			mtx.Lock()
-
-			result = append(result, doc)
+			if strings.HasSuffix(objInfo.Key, "/") {
+				continue // sub folders of non-recursive listings
+			}
 
-			mtx.Unlock()
-		}(objInfo)
+			if !yield(objInfo.Key, nil) {
+				return
+			}
+		}
 	}
+}
 
-	wg.Wait()
-	close(errCh)
-
-	errs := make([]error, 0, len(errCh))
-	for err := range errCh {
-		errs = append(errs, err)
-	}
+func (c *client) ListFiles(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileInfo, error] {
+	return listFiles(ctx, prefix, options, c.listFileInfos, c.describeListedFile)
+}
 
-	if len(errs) > 0 {
-		return nil, fmt.Errorf(errMessage, &DownloadingFilesFailedError{errs})
-	}
+// listFileInfos returns an iterator over the files under the given prefix with the details of the listing.
+func (c *client) listFileInfos(ctx context.Context, prefix, startAfter string, pageSize int) iter.Seq2[*FileInfo, error] {
+	return func(yield func(*FileInfo, error) bool) {
+		listing := c.listObjects(ctx, minio.ListObjectsOptions{
+			Prefix:     prefix,
+			Recursive:  true,
+			StartAfter: startAfter,
+			MaxKeys:    pageSize,
+		})
+
+		for objInfo, err := range listing {
+			if err != nil {
+				yield(nil, handleClientError(err))
 
-	return result, nil
-}
+				return
+			}
 
-func (c *client) GetDirectoryInfos(ctx context.Context, path string) ([]*FileInfo, error) {
-	const errMessage = "failed to get directory: %w"
+			info := &FileInfo{
+				Name:         pathpkg.Base(objInfo.Key),
+				Path:         objInfo.Key,
+				Size:         objInfo.Size,
+				ModifiedDate: objInfo.LastModified,
+			}
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
+			if !yield(info, nil) {
+				return
+			}
+		}
+	}
+}
 
-	objectCh := c.minioClient.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
-		Prefix:    path,
-		Recursive: true,
-	})
+// describeListedFile requests the full file info and the tags of the listed file if the options ask for them.
+func (c *client) describeListedFile(ctx context.Context, listed *FileInfo, opts *listOptions) (*FileInfo, error) {
+	info := listed
 
-	wg := new(sync.WaitGroup)
-	errCh := make(chan error)
-	mtx := new(sync.Mutex)
+	if opts.fullFileInfo {
+		var err error
 
-	result := make([]*FileInfo, 0, len(objectCh))
+		info, err = c.GetFileInfo(ctx, listed.Path)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	for objInfo := range objectCh {
-		if objInfo.Err != nil {
-			return nil, fmt.Errorf(errMessage, objInfo.Err)
+	if opts.tags {
+		tags, err := c.GetTags(ctx, listed.Path)
+		if err != nil {
+			return nil, err
 		}
 
-		wg.Add(1)
+		info.Tags = tags
+	}
 
-		go func(info minio.ObjectInfo) {
-			defer wg.Done()
+	return info, nil
+}
 
-			fileInfo, err := c.GetFileInfo(ctx, info.Key)
-			if err != nil {
-				errCh <- err
+func (c *client) ListFilesPage(ctx context.Context, prefix string, options ...ListOption) (*FilePage, error) {
+	return listFilesPage(ctx, c.ListFiles, prefix, options...)
+}
 
-				return
-			}
+func (c *client) UploadDirectory(
+	ctx context.Context,
+	localPath, prefix string,
+	options ...UploadDirectoryOption,
+) ([]*UploadedFile, error) {
+	const errMessage = "failed to upload directory: %w"
 
-			mtx.Lock()
+	uploaded, err := uploadDirectory(ctx, localPath, prefix, c.concurrency, c.UploadFile, options...)
+	if err != nil {
+		return uploaded, fmt.Errorf(errMessage, err)
+	}
+
+	return uploaded, nil
+}
 
-			result = append(result, fileInfo)
+func (c *client) PlanSync(
+	ctx context.Context,
+	localPath, prefix string,
+	direction SyncDirection,
+	options ...SyncOption,
+) (*SyncPlan, error) {
+	const errMessage = "failed to plan sync: %w"
 
-			mtx.Unlock()
-		}(objInfo)
+	plan, err := planSync(ctx, c, c.algorithms, localPath, prefix, direction, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
 	}
 
-	wg.Wait()
-	close(errCh)
+	return plan, nil
+}
 
-	errs := make([]error, 0, len(errCh))
-	for err := range errCh {
-		errs = append(errs, err)
-	}
+func (c *client) ApplySync(ctx context.Context, plan *SyncPlan) error {
+	const errMessage = "failed to apply sync: %w"
 
-	if len(errs) > 0 {
-		return nil, fmt.Errorf(errMessage, &DownloadingFilesFailedError{errs})
+	if err := applySync(ctx, c, c.concurrency, plan); err != nil {
+		return fmt.Errorf(errMessage, err)
 	}
 
-	return result, nil
+	return nil
 }
 
 func (c *client) DownloadDirectory(ctx context.Context, path, localPath string, recursive bool, options ...DownloadOption) error {
 	const errMessage = "failed to download files from s3: %w"
 
-	doneCh := make(chan struct{})
-	defer close(doneCh)
+	if err := downloadDirectory(ctx, c.listKeys, c.concurrency, path, localPath, recursive, c.DownloadFile, options...); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
 
-	objectCh := c.minioClient.ListObjects(ctx, c.bucketName, minio.ListObjectsOptions{
-		Prefix:    path,
-		Recursive: recursive,
-	})
+	return nil
+}
 
-	wg := new(sync.WaitGroup)
-	errCh := make(chan error)
+func (c *client) RemoveFile(ctx context.Context, path string, options ...RemoveOption) error {
+	const errMessage = "failed to remove file: %w"
 
-	for objInfo := range objectCh {
-		if objInfo.Err != nil {
-			return fmt.Errorf(errMessage, objInfo.Err)
-		}
+	opts := new(removeOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if opts.versionID != "" {
+		opts.clientOptions.VersionID = opts.versionID
+	}
+
+	err := retryCall(ctx, c.retryPolicy, OperationRemoveFile, path, func(ctx context.Context) error {
+		return c.minioClient.RemoveObject(ctx, c.bucketName, path, minio.RemoveObjectOptions(opts.clientOptions))
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return nil
+}
+
+func (c *client) RemoveDirectory(ctx context.Context, path string, options ...RemoveDirectoryOption) ([]string, error) {
+	const errMessage = "failed to remove directory: %w"
+
+	opts := new(removeDirectoryOptions)
 
-		wg.Add(1)
+	for i := range options {
+		options[i](opts)
+	}
 
-		go func(info minio.ObjectInfo) {
-			defer wg.Done()
+	// stops the listing when the removal ends early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-			fileName := strings.TrimPrefix(info.Key, path+"/")
+	var listErr error
 
-			err := c.DownloadFile(ctx, info.Key, localPath+"/"+fileName, options...)
+	objects := func(yield func(minio.ObjectInfo) bool) {
+		listing := c.listObjects(ctx, minio.ListObjectsOptions{
+			Prefix:       folderPrefix(path),
+			Recursive:    true,
+			WithVersions: opts.allVersions,
+		})
+
+		for objInfo, err := range listing {
 			if err != nil {
-				errCh <- err
+				listErr = handleClientError(err)
+
+				return
+			}
+
+			if !yield(objInfo) {
+				return
 			}
-		}(objInfo)
+		}
+	}
+
+	if opts.dryRun {
+		keys := make([]string, 0)
+
+		for objInfo := range objects {
+			keys = append(keys, objInfo.Key)
+		}
+
+		if listErr != nil {
+			return nil, fmt.Errorf(errMessage, listErr)
+		}
+
+		slices.Sort(keys)
+
+		return slices.Compact(keys), nil
+	}
+
+	var err error
+
+	failures := make(map[string]error)
+	keys := make([]string, 0)
+
+	// the objects are removed using multi-object deletes of up to 1000 keys
+	for result := range c.removeObjects(ctx, objects) {
+		if result.ObjectName == "" {
+			err = handleClientError(result.Err)
+
+			continue
+		}
+
+		keys = append(keys, result.ObjectName)
+
+		// a file is only removed once all of its versions are removed
+		if result.Err != nil && failures[result.ObjectName] == nil {
+			failures[result.ObjectName] = handleClientError(result.Err)
+		}
 	}
 
-	wg.Wait()
-	close(errCh)
+	slices.Sort(keys)
+
+	results := make([]bulkResult[string], 0, len(keys))
 
-	errs := make([]error, 0, len(errCh))
-	for err := range errCh {
-		errs = append(errs, err)
+	for _, key := range slices.Compact(keys) {
+		results = append(results, bulkResult[string]{key: key, value: key, err: failures[key]})
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf(errMessage, &DownloadingFilesFailedError{errs})
+	removedKeys, err := collectResults(results, cmp.Or(listErr, err, ctx.Err()), OperationRemoveFile, true)
+	if err != nil {
+		return removedKeys, fmt.Errorf(errMessage, err)
 	}
 
-	return nil
+	return removedKeys, nil
 }
 
-func (c *client) RemoveFile(ctx context.Context, path string, options ...RemoveOption) error {
-	const errMessage = "failed to remove file: %w"
+func (c *client) CopyFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error) {
+	const errMessage = "failed to copy file: %w"
 
-	opts := new(removeOptions)
+	opts := new(copyOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	info, err := c.copyFile(ctx, srcPath, dstPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *client) MoveFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error) {
+	const errMessage = "failed to move file: %w"
+
+	opts := new(copyOptions)
 
 	for i := range options {
 		options[i](opts)
 	}
 
-	if err := c.minioClient.RemoveObject(ctx, c.bucketName, path, minio.RemoveObjectOptions(opts.clientOptions)); err != nil {
+	info, err := c.copyFile(ctx, srcPath, dstPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if srcPath == dstPath && cmp.Or(opts.bucketName, c.bucketName) == c.bucketName {
+		return info, nil
+	}
+
+	// moving a version removes the version instead of adding a delete marker
+	removeOptions := minio.RemoveObjectOptions{VersionID: opts.srcVersionID}
+
+	err = retryCall(ctx, c.retryPolicy, OperationRemoveFile, srcPath, func(ctx context.Context) error {
+		return c.minioClient.RemoveObject(ctx, c.bucketName, srcPath, removeOptions)
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return info, nil
+}
+
+// copyFile copies the file server-side, keeping the metadata, content headers and checksums of the source
+// unless they are replaced by the options.
+func (c *client) copyFile(ctx context.Context, srcPath, dstPath string, opts *copyOptions) (*UploadInfo, error) {
+	srcEncryption := customerKey(c.serverSide(opts.srcEncryption))
+
+	statOptions := minio.StatObjectOptions{
+		VersionID:            opts.srcVersionID,
+		Checksum:             true,
+		ServerSideEncryption: srcEncryption,
+	}
+
+	objInfo, err := retry(ctx, c.retryPolicy, OperationCopyFile, srcPath, func(ctx context.Context) (minio.ObjectInfo, error) {
+		return c.minioClient.StatObject(ctx, c.bucketName, srcPath, statOptions)
+	})
+	if err != nil {
+		return nil, handleClientError(err)
+	}
+
+	native := objectNativeIntegrity(&objInfo)
+	source := &FileInfo{Size: objInfo.Size, MetaData: maps.Clone(objInfo.UserMetadata)}
+
+	stored, err := takeStoredContent(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.transformed() {
+		native = Integrity{}
+	}
+
+	checksums := storedChecksums(source, native)
+	integrity := c.enabledIntegrity(checksums)
+
+	if opts.rotateKey {
+		rotated, err := rewrapKey(ctx, c.keyProvider, stored.envelope)
+		if err != nil {
+			return nil, err
+		}
+
+		if rotated == nil {
+			return &UploadInfo{Size: source.Size, VersionID: objInfo.VersionID, Integrity: integrity}, nil
+		}
+
+		stored.envelope = rotated
+	}
+
+	metaData := source.MetaData
+
+	if opts.replaceMetaData {
+		metaData = maps.Clone(opts.metaData)
+	}
+
+	if metaData == nil {
+		metaData = make(map[string]string)
+	}
+
+	if objInfo.StorageClass != "" {
+		metaData[headerStorageClass] = objInfo.StorageClass
+	}
+
+	dst := minio.CopyDestOptions{
+		Bucket:             cmp.Or(opts.bucketName, c.bucketName),
+		Object:             dstPath,
+		Encryption:         c.serverSide(opts.encryption),
+		ReplaceMetadata:    true,
+		ContentType:        cmp.Or(opts.contentType, objInfo.ContentType, defaultContentType),
+		ContentEncoding:    objInfo.Metadata.Get("Content-Encoding"),
+		ContentDisposition: objInfo.Metadata.Get("Content-Disposition"),
+		ContentLanguage:    objInfo.Metadata.Get("Content-Language"),
+		CacheControl:       objInfo.Metadata.Get("Cache-Control"),
+	}
+
+	// a single copy computes the standard checksum of the copy, while a multipart copy would only produce
+	// a composite checksum, so the checksum is kept in the metadata instead. Like uploads, the copy uses
+	// the native algorithm of the client, all other checksums are kept in the metadata.
+	if algorithm, definition := c.nativeAlgorithm(); definition != nil && objInfo.Size <= maxSingleCopySize &&
+		native.Checksums[algorithm] != "" {
+		dst.ChecksumType = definition.nativeType
+
+		delete(checksums, algorithm)
+	}
+
+	maps.Copy(metaData, checksumMetaData(checksums))
+
+	// the copy keeps the stored content, so it always keeps its encryption and compression
+	maps.Copy(metaData, stored.metaData())
+
+	dst.UserMetadata = metaData
+
+	src := minio.CopySrcOptions{
+		Bucket:     c.bucketName,
+		Object:     srcPath,
+		VersionID:  opts.srcVersionID,
+		MatchETag:  objInfo.ETag,
+		Encryption: srcEncryption,
+	}
+
+	// the content of client-side encrypted files is bound to their path, so copies to another path are re-encrypted
+	reseal := stored.envelope != nil && srcPath != dstPath
+
+	// the copy only succeeds as long as the source matches its etag, so it is safe to retry
+	copied, err := retry(ctx, c.retryPolicy, OperationCopyFile, srcPath, func(ctx context.Context) (minio.UploadInfo, error) {
+		if reseal {
+			return c.resealObject(ctx, dst, src, stored.envelope, objInfo.Size)
+		}
+
+		return c.copyObject(ctx, dst, src, objInfo.Size)
+	})
+	if err != nil {
+		return nil, handleClientError(err)
+	}
+
+	info := &UploadInfo{
+		Size:      source.Size,
+		VersionID: copied.VersionID,
+		Integrity: integrity,
+	}
+
+	return info, nil
+}
+
+func (c *client) EnableVersioning(ctx context.Context) error {
+	const errMessage = "failed to enable versioning: %w"
+
+	err := retryCall(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) error {
+		return c.minioClient.EnableVersioning(ctx, c.bucketName)
+	})
+	if err != nil {
 		return fmt.Errorf(errMessage, handleClientError(err))
 	}
 
 	return nil
 }
 
-func (c *client) CreateFileLink(ctx context.Context, path string, expiration time.Duration) (*url.URL, error) {
+func (c *client) SuspendVersioning(ctx context.Context) error {
+	const errMessage = "failed to suspend versioning: %w"
+
+	err := retryCall(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) error {
+		return c.minioClient.SuspendVersioning(ctx, c.bucketName)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return nil
+}
+
+func (c *client) GetVersioningStatus(ctx context.Context) (VersioningStatus, error) {
+	const errMessage = "failed to get versioning status: %w"
+
+	config, err := retry(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) (minio.BucketVersioningConfiguration, error) {
+		return c.minioClient.GetBucketVersioning(ctx, c.bucketName)
+	})
+	if err != nil {
+		return VersioningUnversioned, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return VersioningStatus(config.Status), nil
+}
+
+func (c *client) ListFileVersions(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileVersion, error] {
+	const errMessage = "failed to list file versions: %w"
+
+	opts := newListOptions(options)
+
+	return func(yield func(*FileVersion, error) bool) {
+		if opts.startAfter != "" || opts.continuationToken != "" {
+			yield(nil, fmt.Errorf(errMessage, fmt.Errorf("%w: start key of version listings", ErrNotSupported)))
+
+			return
+		}
+
+		listing := c.listObjects(ctx, minio.ListObjectsOptions{
+			Prefix:       prefix,
+			Recursive:    true,
+			WithVersions: true,
+			MaxKeys:      opts.pageSize,
+		})
+
+		count := 0
+
+		for objInfo, err := range listing {
+			if err != nil {
+				yield(nil, fmt.Errorf(errMessage, handleClientError(err)))
+
+				return
+			}
+
+			if opts.limitReached(count) {
+				return
+			}
+
+			version := &FileVersion{
+				FileInfo: FileInfo{
+					Name:         pathpkg.Base(objInfo.Key),
+					Path:         objInfo.Key,
+					Size:         objInfo.Size,
+					ModifiedDate: objInfo.LastModified,
+					VersionID:    objInfo.VersionID,
+				},
+				IsLatest:       objInfo.IsLatest,
+				IsDeleteMarker: objInfo.IsDeleteMarker,
+			}
+
+			if opts.fullFileInfo && !objInfo.IsDeleteMarker {
+				info, err := c.GetFileInfo(ctx, objInfo.Key, WithFileInfoVersionID(objInfo.VersionID))
+				if errors.Is(err, ErrNotFound) {
+					continue // removed while listing
+				}
+
+				if err != nil {
+					yield(nil, fmt.Errorf(errMessage, err))
+
+					return
+				}
+
+				version.FileInfo = *info
+			}
+
+			if opts.tags && !objInfo.IsDeleteMarker {
+				tags, err := c.GetTags(ctx, objInfo.Key, WithTagVersionID(objInfo.VersionID))
+				if errors.Is(err, ErrNotFound) {
+					continue // removed while listing
+				}
+
+				if err != nil {
+					yield(nil, fmt.Errorf(errMessage, err))
+
+					return
+				}
+
+				version.Tags = tags
+			}
+
+			count++
+
+			if !yield(version, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *client) RestoreFileVersion(ctx context.Context, path, versionID string) (*UploadInfo, error) {
+	const errMessage = "failed to restore file version: %w"
+
+	info, err := c.copyFile(ctx, path, path, &copyOptions{srcVersionID: versionID})
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *client) RotateFileKey(ctx context.Context, path string) error {
+	const errMessage = "failed to rotate file key: %w"
+
+	if _, err := c.copyFile(ctx, path, path, &copyOptions{rotateKey: true}); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *client) CreateFileLink(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...FileLinkOption,
+) (*url.URL, error) {
 	const errMessage = "failed to create file link: %w"
 
-	link, err := c.minioClient.PresignedGetObject(
+	link, err := c.linkClient.PresignedGetObject(
 		ctx,
 		c.bucketName,
 		path,
 		expiration,
-		c.urlValues,
+		fileLinkValues(c.urlValues, options),
 	)
 	if err != nil {
 		return nil, fmt.Errorf(errMessage, handleClientError(err))
@@ -390,28 +1176,258 @@ func (c *client) CreateFileLink(ctx context.Context, path string, expiration tim
 	return link, nil
 }
 
-func (c *client) AddLifeCycleRule(ctx context.Context, ruleID, folderPath string, daysToExpiry int) error {
-	const (
-		errMessage    = "failed to add lifecycle rule: %w"
-		statusEnabled = "Enabled"
+func (c *client) CreateUploadLink(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...UploadLinkOption,
+) (*UploadLink, error) {
+	const errMessage = "failed to create upload link: %w"
+
+	opts, err := newUploadLinkOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	headers, err := opts.uploadLinkHeaders()
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if sse := c.serverSide(opts.encryption); sse != nil {
+		sse.Marshal(headers)
+	}
+
+	link, err := c.linkClient.PresignHeader(ctx, http.MethodPut, c.bucketName, path, expiration, nil, headers)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return &UploadLink{URL: link, Headers: headers}, nil
+}
+
+func (c *client) CreateUploadPolicy(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...UploadLinkOption,
+) (*UploadPolicy, error) {
+	const errMessage = "failed to create upload policy: %w"
+
+	opts, err := newUploadLinkOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	policy, err := opts.postPolicy(c.bucketName, path, expiration)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	policy.SetEncryption(c.serverSide(opts.encryption))
+
+	link, formData, err := c.linkClient.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return &UploadPolicy{URL: link, FormData: formData}, nil
+}
+
+func (c *client) SetRetention(ctx context.Context, path string, retention *Retention, options ...LockOption) error {
+	const errMessage = "failed to set retention: %w"
+
+	opts := new(lockOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	retentionOptions := minio.PutObjectRetentionOptions{
+		GovernanceBypass: opts.governanceBypass,
+		VersionID:        opts.versionID,
+	}
+
+	if retention != nil {
+		if err := retention.validate(); err != nil {
+			return fmt.Errorf(errMessage, err)
+		}
+
+		mode := minio.RetentionMode(retention.Mode)
+
+		retentionOptions.Mode = &mode
+		retentionOptions.RetainUntilDate = &retention.RetainUntilDate
+	}
+
+	err := retryCall(ctx, c.retryPolicy, OperationLockFile, path, func(ctx context.Context) error {
+		return c.minioClient.PutObjectRetention(ctx, c.bucketName, path, retentionOptions)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return nil
+}
+
+func (c *client) GetRetention(ctx context.Context, path string, options ...LockOption) (*Retention, error) {
+	const errMessage = "failed to get retention: %w"
+
+	opts := new(lockOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	var (
+		mode            *minio.RetentionMode
+		retainUntilDate *time.Time
 	)
 
-	if !strings.HasSuffix(folderPath, "/") {
-		folderPath += "/"
+	err := retryCall(ctx, c.retryPolicy, OperationLockFile, path, func(ctx context.Context) error {
+		var err error
+
+		mode, retainUntilDate, err = c.minioClient.GetObjectRetention(ctx, c.bucketName, path, opts.versionID)
+
+		return err
+	})
+	if isNoLockConfig(err) {
+		return nil, nil //nolint:nilnil // files without retention
 	}
 
-	err := c.minioClient.SetBucketLifecycle(ctx, c.bucketName, &lifecycle.Configuration{
-		XMLName: xml.Name{},
-		Rules: []lifecycle.Rule{
-			{
-				ID: ruleID,
-				Expiration: lifecycle.Expiration{
-					Days: lifecycle.ExpirationDays(daysToExpiry),
-				},
-				Prefix: folderPath,
-				Status: statusEnabled,
-			},
-		},
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	if mode == nil || *mode == "" || retainUntilDate == nil {
+		return nil, nil //nolint:nilnil // files without retention
+	}
+
+	retention := &Retention{
+		Mode:            RetentionMode(*mode),
+		RetainUntilDate: *retainUntilDate,
+	}
+
+	return retention, nil
+}
+
+func (c *client) SetLegalHold(ctx context.Context, path string, enabled bool, options ...LockOption) error {
+	const errMessage = "failed to set legal hold: %w"
+
+	opts := new(lockOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	status := legalHoldStatus(enabled)
+
+	legalHoldOptions := minio.PutObjectLegalHoldOptions{
+		VersionID: opts.versionID,
+		Status:    &status,
+	}
+
+	err := retryCall(ctx, c.retryPolicy, OperationLockFile, path, func(ctx context.Context) error {
+		return c.minioClient.PutObjectLegalHold(ctx, c.bucketName, path, legalHoldOptions)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return nil
+}
+
+func (c *client) GetLegalHold(ctx context.Context, path string, options ...LockOption) (bool, error) {
+	const errMessage = "failed to get legal hold: %w"
+
+	opts := new(lockOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	status, err := retry(ctx, c.retryPolicy, OperationLockFile, path, func(ctx context.Context) (*minio.LegalHoldStatus, error) {
+		return c.minioClient.GetObjectLegalHold(ctx, c.bucketName, path, minio.GetObjectLegalHoldOptions{VersionID: opts.versionID})
+	})
+	if isNoLockConfig(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return status != nil && *status == minio.LegalHoldEnabled, nil
+}
+
+func (c *client) GetObjectLockConfig(ctx context.Context) (*ObjectLockConfig, error) {
+	const errMessage = "failed to get object lock config: %w"
+
+	var (
+		objectLock string
+		mode       *minio.RetentionMode
+		validity   *uint
+		unit       *minio.ValidityUnit
+	)
+
+	err := retryCall(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) error {
+		var err error
+
+		objectLock, mode, validity, unit, err = c.minioClient.GetObjectLockConfig(ctx, c.bucketName)
+
+		return err
+	})
+	if isNoLockConfig(err) {
+		return &ObjectLockConfig{}, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	config := &ObjectLockConfig{
+		Enabled: objectLock == "Enabled",
+	}
+
+	if mode != nil && validity != nil && unit != nil {
+		config.DefaultRetention = &DefaultRetention{Mode: RetentionMode(*mode)}
+
+		if *unit == minio.Years {
+			config.DefaultRetention.Years = int(*validity)
+		} else {
+			config.DefaultRetention.Days = int(*validity)
+		}
+	}
+
+	return config, nil
+}
+
+func (c *client) GetTags(ctx context.Context, path string, options ...TagOption) (map[string]string, error) {
+	const errMessage = "failed to get tags: %w"
+
+	opts := newTagOptions(options)
+
+	objTags, err := retry(ctx, c.retryPolicy, OperationTagFile, path, func(ctx context.Context) (*tags.Tags, error) {
+		return c.minioClient.GetObjectTagging(ctx, c.bucketName, path, minio.GetObjectTaggingOptions{VersionID: opts.versionID})
+	})
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return objTags.ToMap(), nil
+}
+
+func (c *client) PutTags(ctx context.Context, path string, tags map[string]string, options ...TagOption) error {
+	const errMessage = "failed to put tags: %w"
+
+	opts := newTagOptions(options)
+
+	objTags, err := objectTags(tags)
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	err = retryCall(ctx, c.retryPolicy, OperationTagFile, path, func(ctx context.Context) error {
+		return c.minioClient.PutObjectTagging(ctx, c.bucketName, path, objTags, minio.PutObjectTaggingOptions{VersionID: opts.versionID})
 	})
 	if err != nil {
 		return fmt.Errorf(errMessage, handleClientError(err))
@@ -419,3 +1435,135 @@ func (c *client) AddLifeCycleRule(ctx context.Context, ruleID, folderPath string
 
 	return nil
 }
+
+func (c *client) RemoveTags(ctx context.Context, path string, options ...TagOption) error {
+	const errMessage = "failed to remove tags: %w"
+
+	opts := newTagOptions(options)
+
+	err := retryCall(ctx, c.retryPolicy, OperationTagFile, path, func(ctx context.Context) error {
+		return c.minioClient.RemoveObjectTagging(ctx, c.bucketName, path, minio.RemoveObjectTaggingOptions{VersionID: opts.versionID})
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, handleClientError(err))
+	}
+
+	return nil
+}
+
+func (c *client) AddLifeCycleRule(ctx context.Context, ruleID, folderPath string, daysToExpiry int) error {
+	const errMessage = "failed to add lifecycle rule: %w"
+
+	rule := &LifecycleRule{
+		ID:             ruleID,
+		Prefix:         folderPrefix(folderPath),
+		ExpirationDays: daysToExpiry,
+	}
+
+	if err := c.PutLifecycleRule(ctx, rule); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *client) GetLifecycleRules(ctx context.Context) ([]*LifecycleRule, error) {
+	const errMessage = "failed to get lifecycle rules: %w"
+
+	config, err := c.getLifecycle(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return lifecycleRules(config), nil
+}
+
+func (c *client) GetLifecycleRule(ctx context.Context, ruleID string) (*LifecycleRule, error) {
+	const errMessage = "failed to get lifecycle rule: %w"
+
+	config, err := c.getLifecycle(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	rule, err := findLifecycleRule(config, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return rule, nil
+}
+
+func (c *client) PutLifecycleRule(ctx context.Context, rule *LifecycleRule) error {
+	const errMessage = "failed to put lifecycle rule: %w"
+
+	err := c.updateLifecycle(ctx, func(config *lifecycle.Configuration) error {
+		return putLifecycleRule(config, rule)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *client) RemoveLifecycleRule(ctx context.Context, ruleID string) error {
+	const errMessage = "failed to remove lifecycle rule: %w"
+
+	err := c.updateLifecycle(ctx, func(config *lifecycle.Configuration) error {
+		return removeLifecycleRule(config, ruleID)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+// getLifecycle returns the lifecycle configuration of the bucket. A bucket without configuration has no rules.
+func (c *client) getLifecycle(ctx context.Context) (*lifecycle.Configuration, error) {
+	const noSuchLifecycleConfiguration = "NoSuchLifecycleConfiguration"
+
+	config, err := retry(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) (*lifecycle.Configuration, error) {
+		return c.minioClient.GetBucketLifecycle(ctx, c.bucketName)
+	})
+	if err != nil {
+		err = handleClientError(err)
+
+		var requestErr *RequestError
+
+		if errors.As(err, &requestErr) && requestErr.Code == noSuchLifecycleConfiguration {
+			return lifecycle.NewConfiguration(), nil
+		}
+
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// updateLifecycle applies the update to the current lifecycle configuration of the bucket.
+// S3 only allows to replace the whole configuration, so updates of the same client are serialized.
+// Concurrent updates by other clients may still get lost.
+func (c *client) updateLifecycle(ctx context.Context, update func(config *lifecycle.Configuration) error) error {
+	c.lifecycleMtx.Lock()
+	defer c.lifecycleMtx.Unlock()
+
+	config, err := c.getLifecycle(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := update(config); err != nil {
+		return err
+	}
+
+	err = retryCall(ctx, c.retryPolicy, OperationConfigureBucket, "", func(ctx context.Context) error {
+		return c.minioClient.SetBucketLifecycle(ctx, c.bucketName, config)
+	})
+	if err != nil {
+		return handleClientError(err)
+	}
+
+	return nil
+}
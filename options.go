@@ -2,6 +2,7 @@ package s3 //nolint:revive // package name matches folder name
 
 import (
 	"fmt"
+	"maps"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -12,13 +13,8 @@ type ClientOption func(*client) error
 
 // WithHealthCheck enables the health check for the s3 client.
 func WithHealthCheck(interval time.Duration) ClientOption {
-	const errMessage = "failed to enable health check: %w"
-
-	return func(c *client) (err error) { //nolint:nonamedreturns // intended
-		c.cancelFunc, err = c.minioClient.HealthCheck(interval)
-		if err != nil {
-			return fmt.Errorf(errMessage, err)
-		}
+	return func(c *client) error {
+		c.healthCheck = interval
 
 		return nil
 	}
@@ -27,18 +23,117 @@ func WithHealthCheck(interval time.Duration) ClientOption {
 // WithCRC32CIntegritySupport enables or disables CRC32C integrity check support.
 // By default it's enabled.
 func WithCRC32CIntegritySupport(enabled bool) ClientOption {
+	return WithIntegritySupport(ChecksumAlgorithmCRC32C, enabled)
+}
+
+// WithMD5IntegritySupport enables or disables MD5 integrity check support.
+// By default it's disabled.
+func WithMD5IntegritySupport(enabled bool) ClientOption {
+	return WithIntegritySupport(ChecksumAlgorithmMD5, enabled)
+}
+
+// WithIntegritySupport enables or disables integrity check support for the given checksum algorithm.
+// Any number of algorithms can be enabled, all of them are computed in a single pass over the data.
+// By default only CRC32C is enabled.
+func WithIntegritySupport(algorithm ChecksumAlgorithm, enabled bool) ClientOption {
+	const errMessage = "failed to set integrity support: %w"
+
 	return func(c *client) error {
-		c.useIntegrityCRC32C = enabled
+		if err := c.setIntegritySupport(algorithm, enabled); err != nil {
+			return fmt.Errorf(errMessage, err)
+		}
 
 		return nil
 	}
 }
 
-// WithMD5IntegritySupport enables or disables MD5 integrity check support.
+// WithNativeChecksums enables or disables sending a checksum in the standard x-amz-checksum-* header
+// instead of the user metadata, so the server verifies the checksum on write.
+// S3 supports a single standard checksum per object, so the first enabled algorithm with a standard header
+// (CRC32C, CRC32, CRC64NVME, SHA1 or SHA256) is used. All other checksums are stored in the user metadata,
+// as are composite checksums of multipart uploads, which cannot be compared with a checksum of the content.
+// Checksums of files uploaded without native checksums are still read from the user metadata.
 // By default it's disabled.
-func WithMD5IntegritySupport(enabled bool) ClientOption {
+func WithNativeChecksums(enabled bool) ClientOption {
+	return func(c *client) error {
+		c.useNativeChecksums = enabled
+
+		return nil
+	}
+}
+
+// WithConcurrency limits the number of files processed at the same time by GetDirectory, GetDirectoryInfos,
+// DownloadDirectory and UploadDirectory. The limit can be overridden per call. By default it's 16.
+func WithConcurrency(limit int) ClientOption {
+	const errMessage = "failed to set concurrency: %w"
+
+	return func(c *client) error {
+		if limit < 1 {
+			return fmt.Errorf(errMessage, ErrInvalidConcurrency)
+		}
+
+		c.concurrency = limit
+
+		return nil
+	}
+}
+
+type publicEndpoint struct {
+	host   string
+	secure bool
+}
+
+// WithPublicEndpoint signs presigned links for the given host instead of the host of the client details,
+// e.g. when the client connects to an internal endpoint but links are handed to browsers outside the cluster.
+// It only applies to the s3 backed client.
+func WithPublicEndpoint(host string, secure bool) ClientOption {
+	const errMessage = "failed to set public endpoint: %w"
+
+	return func(c *client) error {
+		if host == "" {
+			return fmt.Errorf(errMessage, ErrEmptyHost)
+		}
+
+		c.publicEndpoint = &publicEndpoint{host: host, secure: secure}
+
+		return nil
+	}
+}
+
+// WithDefaultEncryption encrypts all uploaded and copied files server-side, and sends the key of SSE-C encryption
+// with every request reading a file. It can be overridden per call, e.g. by NoEncryption for files which are
+// not encrypted with SSE-C. It only applies to the s3 backed client.
+func WithDefaultEncryption(encryption *Encryption) ClientOption {
+	return func(c *client) error {
+		c.encryption = encryption
+
+		return nil
+	}
+}
+
+// WithClientSideEncryption encrypts the content of all uploaded files with AES-256-GCM before it leaves the client,
+// using a random data key per file wrapped by the key provider. Encrypted files are decrypted when reading them,
+// files which are not encrypted are read as they are. It applies to all clients.
+func WithClientSideEncryption(provider KeyProvider) ClientOption {
+	return func(c *client) error {
+		c.keyProvider = provider
+
+		return nil
+	}
+}
+
+// WithRetryPolicy retries failed requests according to the policy instead of the internal retries of minio-go,
+// which are disabled. Uploads of streams are not retried, since their content cannot be read again.
+// It only applies to the s3 backed client.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	const errMessage = "failed to set retry policy: %w"
+
 	return func(c *client) error {
-		c.useIntegrityMD5 = enabled
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf(errMessage, err)
+		}
+
+		c.retryPolicy = policy
 
 		return nil
 	}
@@ -49,6 +144,10 @@ type ClientUploadOptions minio.PutObjectOptions
 
 type uploadOptions struct {
 	clientOptions ClientUploadOptions
+	retention     *Retention
+	legalHold     bool
+	encryption    *Encryption
+	compression   Compression
 }
 
 // UploadOption is an option for uploading a file.
@@ -61,9 +160,43 @@ func WithClientUploadOptions(options ClientUploadOptions) UploadOption {
 	}
 }
 
+// WithEncryption encrypts the uploaded file server-side, overriding the default encryption of the client.
+func WithEncryption(encryption *Encryption) UploadOption {
+	return func(o *uploadOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithCompression compresses the content of the uploaded file while it streams, recording the compression and
+// the size of the uncompressed content in the metadata. Compressed files are decompressed when they are read.
+func WithCompression(compression Compression) UploadOption {
+	return func(o *uploadOptions) {
+		o.compression = compression
+	}
+}
+
+// WithRetention protects the uploaded file from being deleted or overwritten until the given date.
+// The bucket must have object lock enabled.
+func WithRetention(mode RetentionMode, retainUntilDate time.Time) UploadOption {
+	return func(o *uploadOptions) {
+		o.retention = &Retention{Mode: mode, RetainUntilDate: retainUntilDate}
+	}
+}
+
+// WithLegalHold protects the uploaded file from being deleted or overwritten until the legal hold is removed.
+// The bucket must have object lock enabled.
+func WithLegalHold() UploadOption {
+	return func(o *uploadOptions) {
+		o.legalHold = true
+	}
+}
+
 type getOptions struct {
 	clientOptions ClientGetOptions
-	Integrity
+	versionID     string
+	checksums     map[ChecksumAlgorithm]string
+	autoVerify    bool
+	encryption    *Encryption
 }
 
 // GetOption is an option for getting a file.
@@ -79,22 +212,79 @@ func WithClientGetOptions(options ClientGetOptions) GetOption {
 	}
 }
 
-// WithIntegrityCheckCRC32C checks if the CRC32C checksum of the downloaded file matches the given checksum.
-func WithIntegrityCheckCRC32C(checksum string) GetOption {
+// WithVersionID gets the given version of the file instead of the current version.
+func WithVersionID(versionID string) GetOption {
 	return func(o *getOptions) {
-		o.ChecksumCRC32C = checksum
+		o.versionID = versionID
 	}
 }
 
+// WithGetEncryption sets the encryption of the file, overriding the default encryption of the client.
+// Only the key of SSE-C encryption is sent, s3 decrypts all other files transparently.
+func WithGetEncryption(encryption *Encryption) GetOption {
+	return func(o *getOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithIntegrityCheckCRC32C checks if the CRC32C checksum of the downloaded file matches the given checksum.
+func WithIntegrityCheckCRC32C(checksum string) GetOption {
+	return WithIntegrityCheck(ChecksumAlgorithmCRC32C, checksum)
+}
+
 // WithIntegrityCheckMD5 checks if the MD5 checksum of the downloaded file matches the given checksum.
 func WithIntegrityCheckMD5(checksum string) GetOption {
+	return WithIntegrityCheck(ChecksumAlgorithmMD5, checksum)
+}
+
+// WithIntegrityCheck checks if the checksum of the given algorithm of the downloaded file matches the given hex encoded checksum.
+// The algorithm does not need to be enabled for the client.
+// The content is verified while it is read, a mismatch is returned by the final Read or by Close of the file.
+func WithIntegrityCheck(algorithm ChecksumAlgorithm, checksum string) GetOption {
+	return func(o *getOptions) {
+		if o.checksums == nil {
+			o.checksums = make(map[ChecksumAlgorithm]string)
+		}
+
+		o.checksums[algorithm] = checksum
+	}
+}
+
+// WithAutoIntegrityCheck verifies the content of the downloaded file against all checksums stored with the file,
+// without the need to provide the expected checksums. Files without stored checksums are not verified.
+// The content is verified while it is read, a mismatch is returned by the final Read or by Close of the file.
+func WithAutoIntegrityCheck() GetOption {
 	return func(o *getOptions) {
-		o.ChecksumMD5 = checksum
+		o.autoVerify = true
+	}
+}
+
+type fileInfoOptions struct {
+	versionID  string
+	encryption *Encryption
+}
+
+// FileInfoOption is an option for getting the information of a file.
+type FileInfoOption func(*fileInfoOptions)
+
+// WithFileInfoVersionID gets the information of the given version of the file instead of the current version.
+func WithFileInfoVersionID(versionID string) FileInfoOption {
+	return func(o *fileInfoOptions) {
+		o.versionID = versionID
+	}
+}
+
+// WithFileInfoEncryption sets the encryption of the file, overriding the default encryption of the client.
+// Only the key of SSE-C encryption is sent, s3 returns the information of all other files transparently.
+func WithFileInfoEncryption(encryption *Encryption) FileInfoOption {
+	return func(o *fileInfoOptions) {
+		o.encryption = encryption
 	}
 }
 
 type getDirectoryOptions struct {
 	clientOptions ClientGetOptions
+	bulkOptions
 }
 
 // GetDirectoryOption is an option for getting a file.
@@ -107,8 +297,90 @@ func WithGetDirectoryClientGetOptions(options ClientGetOptions) GetDirectoryOpti
 	}
 }
 
+// WithGetDirectoryConcurrency limits the number of files requested at the same time, overriding the limit of the client.
+func WithGetDirectoryConcurrency(limit int) GetDirectoryOption {
+	return func(o *getDirectoryOptions) {
+		o.concurrency = limit
+	}
+}
+
+// WithGetDirectoryFailFast cancels the remaining requests as soon as requesting a file failed.
+func WithGetDirectoryFailFast() GetDirectoryOption {
+	return func(o *getDirectoryOptions) {
+		o.failFast = true
+	}
+}
+
+// WithGetDirectoryPartialResults returns the files requested successfully together with the BulkError
+// describing the failed files, instead of discarding them.
+func WithGetDirectoryPartialResults() GetDirectoryOption {
+	return func(o *getDirectoryOptions) {
+		o.partial = true
+	}
+}
+
+type listOptions struct {
+	pageSize          int
+	startAfter        string
+	maxKeys           int
+	continuationToken string
+	fullFileInfo      bool
+	tags              bool
+}
+
+// ListOption is an option for listing files.
+type ListOption func(*listOptions)
+
+// WithPageSize sets the number of keys requested from s3 per request. By default it's 1000, which is also the s3 maximum.
+func WithPageSize(pageSize int) ListOption {
+	return func(o *listOptions) {
+		o.pageSize = pageSize
+	}
+}
+
+// WithStartAfter starts the listing after the given key.
+func WithStartAfter(key string) ListOption {
+	return func(o *listOptions) {
+		o.startAfter = key
+	}
+}
+
+// WithMaxKeys limits the total number of listed files. For ListFilesPage it sets the size of the page.
+func WithMaxKeys(maxKeys int) ListOption {
+	return func(o *listOptions) {
+		o.maxKeys = maxKeys
+	}
+}
+
+// WithContinuationToken continues a listing using the token of a previous FilePage. It takes precedence over WithStartAfter.
+func WithContinuationToken(token string) ListOption {
+	return func(o *listOptions) {
+		o.continuationToken = token
+	}
+}
+
+// WithFileTags requests the tags of every listed file.
+// Note: This requires an additional request per file.
+func WithFileTags() ListOption {
+	return func(o *listOptions) {
+		o.tags = true
+	}
+}
+
+// WithFullFileInfo requests the complete file info including content type, metadata and checksums for every listed file.
+// By default only the name, path, size and modified date are set, since s3 does not return more while listing.
+// Note: This requires an additional request per file.
+func WithFullFileInfo() ListOption {
+	return func(o *listOptions) {
+		o.fullFileInfo = true
+	}
+}
+
 type downloadOptions struct {
 	clientOptions ClientGetOptions
+	versionID     string
+	encryption    *Encryption
+	bulkOptions
 }
 
 // DownloadOption is an option for downloading a file.
@@ -121,11 +393,51 @@ func WithClientDownloadOptions(options ClientGetOptions) DownloadOption {
 	}
 }
 
+// WithDownloadVersionID downloads the given version of the file instead of the current version.
+// It only applies to DownloadFile, DownloadDirectory ignores it.
+func WithDownloadVersionID(versionID string) DownloadOption {
+	return func(o *downloadOptions) {
+		o.versionID = versionID
+	}
+}
+
+// withoutDownloadVersionID removes the version from the options, since a version only identifies a single file.
+func withoutDownloadVersionID() DownloadOption {
+	return func(o *downloadOptions) {
+		o.versionID = ""
+		o.clientOptions.VersionID = ""
+	}
+}
+
+// WithDownloadEncryption sets the encryption of the downloaded files, overriding the default encryption of the client.
+// Only the key of SSE-C encryption is sent, s3 decrypts all other files transparently.
+func WithDownloadEncryption(encryption *Encryption) DownloadOption {
+	return func(o *downloadOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithDownloadConcurrency limits the number of files downloaded at the same time by DownloadDirectory,
+// overriding the limit of the client.
+func WithDownloadConcurrency(limit int) DownloadOption {
+	return func(o *downloadOptions) {
+		o.concurrency = limit
+	}
+}
+
+// WithDownloadFailFast cancels the remaining downloads of DownloadDirectory as soon as a download failed.
+func WithDownloadFailFast() DownloadOption {
+	return func(o *downloadOptions) {
+		o.failFast = true
+	}
+}
+
 // ClientRemoveOptions is an alias for minio.RemoveObjectOptions.
 type ClientRemoveOptions minio.RemoveObjectOptions
 
 type removeOptions struct {
 	clientOptions ClientRemoveOptions
+	versionID     string
 }
 
 // RemoveOption is an option for removing a file.
@@ -137,3 +449,355 @@ func WithClientRemoveOptions(options ClientRemoveOptions) RemoveOption {
 		o.clientOptions = options
 	}
 }
+
+// WithRemoveVersionID permanently deletes the given version of the file, instead of adding a delete marker
+// in a versioned bucket.
+func WithRemoveVersionID(versionID string) RemoveOption {
+	return func(o *removeOptions) {
+		o.versionID = versionID
+	}
+}
+
+type copyOptions struct {
+	bucketName      string
+	srcVersionID    string
+	metaData        map[string]string
+	replaceMetaData bool
+	contentType     string
+	encryption      *Encryption
+	srcEncryption   *Encryption
+	rotateKey       bool // re-wraps the data key of the client-side encrypted file
+}
+
+// CopyOption is an option for copying or moving a file.
+type CopyOption func(*copyOptions)
+
+// WithDestinationBucket copies the file into the given bucket instead of the bucket of the client.
+func WithDestinationBucket(bucketName string) CopyOption {
+	return func(o *copyOptions) {
+		o.bucketName = bucketName
+	}
+}
+
+// WithSourceVersionID copies the given version of the source file instead of the current version.
+func WithSourceVersionID(versionID string) CopyOption {
+	return func(o *copyOptions) {
+		o.srcVersionID = versionID
+	}
+}
+
+// WithCopyEncryption encrypts the copy server-side, overriding the default encryption of the client.
+// The encryption of the source file is not carried across.
+func WithCopyEncryption(encryption *Encryption) CopyOption {
+	return func(o *copyOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithSourceEncryption sets the encryption of the source file, overriding the default encryption of the client.
+// Only the key of SSE-C encryption is sent, s3 decrypts all other files transparently.
+func WithSourceEncryption(encryption *Encryption) CopyOption {
+	return func(o *copyOptions) {
+		o.srcEncryption = encryption
+	}
+}
+
+// WithCopyMetaData replaces the metadata of the copy with the given metadata.
+// By default the metadata of the source file is kept. The checksums are carried across in any case.
+func WithCopyMetaData(metaData map[string]string) CopyOption {
+	return func(o *copyOptions) {
+		o.metaData = metaData
+		o.replaceMetaData = true
+	}
+}
+
+// WithCopyContentType replaces the content type of the copy.
+// By default the content type of the source file is kept.
+func WithCopyContentType(contentType string) CopyOption {
+	return func(o *copyOptions) {
+		o.contentType = contentType
+	}
+}
+
+type removeDirectoryOptions struct {
+	dryRun      bool
+	allVersions bool
+}
+
+// RemoveDirectoryOption is an option for removing a directory.
+type RemoveDirectoryOption func(*removeDirectoryOptions)
+
+// WithRemoveDirectoryDryRun only lists the files which would be removed, without removing them.
+func WithRemoveDirectoryDryRun() RemoveDirectoryOption {
+	return func(o *removeDirectoryOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithRemoveAllVersions removes all versions and delete markers of the files in a versioned bucket,
+// instead of just adding delete markers.
+func WithRemoveAllVersions() RemoveDirectoryOption {
+	return func(o *removeDirectoryOptions) {
+		o.allVersions = true
+	}
+}
+
+// SymlinkPolicy defines how UploadDirectory handles symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkSkip skips symbolic links.
+	SymlinkSkip SymlinkPolicy = iota
+	// SymlinkFollow uploads the targets of symbolic links as if they were located at the link.
+	// Links pointing to a parent folder are skipped to avoid loops.
+	SymlinkFollow
+)
+
+type uploadDirectoryOptions struct {
+	bulkOptions
+	localFilter
+	uploadOptions []UploadOption
+}
+
+// UploadDirectoryOption is an option for uploading a directory.
+type UploadDirectoryOption func(*uploadDirectoryOptions)
+
+// WithDirectoryUploadOptions applies the given upload options to the upload of every file.
+func WithDirectoryUploadOptions(options ...UploadOption) UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.uploadOptions = append(o.uploadOptions, options...)
+	}
+}
+
+// WithUploadDirectoryConcurrency limits the number of files uploaded at the same time, overriding the limit of the client.
+func WithUploadDirectoryConcurrency(limit int) UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.concurrency = limit
+	}
+}
+
+// WithUploadDirectoryFailFast cancels the remaining uploads as soon as an upload failed.
+func WithUploadDirectoryFailFast() UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.failFast = true
+	}
+}
+
+// WithInclude only uploads the files matching any of the given glob patterns, see path.Match for the syntax.
+// The patterns are matched against the slash separated path relative to the uploaded folder,
+// patterns without a slash are also matched against the file name.
+func WithInclude(patterns ...string) UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.include = append(o.include, patterns...)
+	}
+}
+
+// WithExclude skips the files and folders matching any of the given glob patterns, see WithInclude for the matching rules.
+func WithExclude(patterns ...string) UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.exclude = append(o.exclude, patterns...)
+	}
+}
+
+// WithSymlinkPolicy defines how symbolic links are handled. By default they are skipped.
+func WithSymlinkPolicy(policy SymlinkPolicy) UploadDirectoryOption {
+	return func(o *uploadDirectoryOptions) {
+		o.symlinks = policy
+	}
+}
+
+type syncOptions struct {
+	bulkOptions
+	delete bool
+}
+
+// SyncOption is an option for planning a sync.
+type SyncOption func(*syncOptions)
+
+// WithSyncDelete deletes the files of the destination which are missing on the source.
+func WithSyncDelete() SyncOption {
+	return func(o *syncOptions) {
+		o.delete = true
+	}
+}
+
+// WithSyncConcurrency limits the number of files transferred at the same time when applying the plan,
+// overriding the limit of the client.
+func WithSyncConcurrency(limit int) SyncOption {
+	return func(o *syncOptions) {
+		o.concurrency = limit
+	}
+}
+
+// WithSyncFailFast cancels the remaining transfers as soon as a transfer failed when applying the plan.
+func WithSyncFailFast() SyncOption {
+	return func(o *syncOptions) {
+		o.failFast = true
+	}
+}
+
+type uploadLinkOptions struct {
+	contentType      string
+	metaData         map[string]string
+	checksums        map[ChecksumAlgorithm]string
+	requiredMetaData []string
+	requiredSums     []ChecksumAlgorithm
+	minSize          int64
+	maxSize          int64
+	pathPrefix       bool
+	encryption       *Encryption
+}
+
+// UploadLinkOption is an option for creating a presigned upload link or upload policy.
+type UploadLinkOption func(*uploadLinkOptions)
+
+// WithUploadLinkContentType requires the upload to have the given content type.
+func WithUploadLinkContentType(contentType string) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.contentType = contentType
+	}
+}
+
+// WithUploadLinkMetaData requires the upload to carry the given metadata.
+func WithUploadLinkMetaData(metaData map[string]string) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		if o.metaData == nil {
+			o.metaData = make(map[string]string, len(metaData))
+		}
+
+		maps.Copy(o.metaData, metaData)
+	}
+}
+
+// WithUploadLinkChecksum requires the upload to carry the given hex encoded checksum in the metadata key
+// this library reads the checksums of the algorithm from, so the integrity of downloads can be verified.
+func WithUploadLinkChecksum(algorithm ChecksumAlgorithm, checksum string) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		if o.checksums == nil {
+			o.checksums = make(map[ChecksumAlgorithm]string)
+		}
+
+		o.checksums[algorithm] = checksum
+	}
+}
+
+// WithRequiredMetaData requires the upload form to contain the given metadata fields with any value.
+// It only applies to upload policies, since presigned upload links can only require known values.
+func WithRequiredMetaData(keys ...string) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.requiredMetaData = append(o.requiredMetaData, keys...)
+	}
+}
+
+// WithRequiredChecksums requires the upload form to contain the metadata fields this library reads the checksums
+// of the given algorithms from, so checksums computed by the browser can be verified on download.
+// It only applies to upload policies.
+func WithRequiredChecksums(algorithms ...ChecksumAlgorithm) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.requiredSums = append(o.requiredSums, algorithms...)
+	}
+}
+
+// WithContentLengthRange requires the size of the upload to be within the given range in bytes.
+// It only applies to upload policies, presigned upload links cannot restrict the size.
+func WithContentLengthRange(minSize, maxSize int64) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.minSize = minSize
+		o.maxSize = maxSize
+	}
+}
+
+// WithUploadLinkEncryption requires the upload to be encrypted server-side, overriding the default encryption
+// of the client. The encryption headers are part of the upload link, so SSE-C keys are handed to the uploader.
+func WithUploadLinkEncryption(encryption *Encryption) UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.encryption = encryption
+	}
+}
+
+// WithPathPrefix allows uploading to any path starting with the given path, instead of the path itself.
+// The browser sets the path in the key field of the form. It only applies to upload policies.
+func WithPathPrefix() UploadLinkOption {
+	return func(o *uploadLinkOptions) {
+		o.pathPrefix = true
+	}
+}
+
+type fileLinkOptions struct {
+	disposition  string
+	fileName     string
+	contentType  string
+	cacheControl string
+}
+
+// FileLinkOption is an option for creating a presigned download link.
+type FileLinkOption func(*fileLinkOptions)
+
+// WithAttachment makes browsers download the file instead of displaying it.
+// If the file name is not empty, it is used as the name of the downloaded file. Non ASCII names are encoded
+// according to RFC 5987, with an ASCII fallback for older browsers.
+func WithAttachment(fileName string) FileLinkOption {
+	return func(o *fileLinkOptions) {
+		o.disposition = "attachment"
+		o.fileName = fileName
+	}
+}
+
+// WithInlineFileName keeps displaying the file in browsers, but uses the given name when the file is saved.
+func WithInlineFileName(fileName string) FileLinkOption {
+	return func(o *fileLinkOptions) {
+		o.disposition = "inline"
+		o.fileName = fileName
+	}
+}
+
+// WithResponseContentType overrides the content type of the file in the response to the link.
+func WithResponseContentType(contentType string) FileLinkOption {
+	return func(o *fileLinkOptions) {
+		o.contentType = contentType
+	}
+}
+
+// WithResponseCacheControl sets the Cache-Control header of the response to the link.
+func WithResponseCacheControl(cacheControl string) FileLinkOption {
+	return func(o *fileLinkOptions) {
+		o.cacheControl = cacheControl
+	}
+}
+
+type lockOptions struct {
+	versionID        string
+	governanceBypass bool
+}
+
+// LockOption is an option for managing the retention and legal hold of a file.
+type LockOption func(*lockOptions)
+
+// WithLockVersionID manages the lock of the given version of the file instead of the current version.
+func WithLockVersionID(versionID string) LockOption {
+	return func(o *lockOptions) {
+		o.versionID = versionID
+	}
+}
+
+// WithGovernanceBypass allows shortening or removing a retention in governance mode.
+// It requires the s3:BypassGovernanceRetention permission.
+func WithGovernanceBypass() LockOption {
+	return func(o *lockOptions) {
+		o.governanceBypass = true
+	}
+}
+
+type tagOptions struct {
+	versionID string
+}
+
+// TagOption is an option for managing the tags of a file.
+type TagOption func(*tagOptions)
+
+// WithTagVersionID manages the tags of the given version of the file instead of the current version.
+func WithTagVersionID(versionID string) TagOption {
+	return func(o *tagOptions) {
+		o.versionID = versionID
+	}
+}
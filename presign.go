@@ -0,0 +1,246 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	metaDataHeaderPrefix = "x-amz-meta-"
+
+	queryContentDisposition = "response-content-disposition"
+	queryContentType        = "response-content-type"
+	queryCacheControl       = "response-cache-control"
+)
+
+// UploadLink is a presigned link to upload a file using a PUT request.
+type UploadLink struct {
+	URL *url.URL
+	// Headers are part of the signature and have to be sent with the upload request.
+	Headers http.Header
+}
+
+// UploadPolicy is a presigned policy to upload a file from a browser using a POST form.
+type UploadPolicy struct {
+	URL *url.URL
+	// FormData are the fields the form has to contain in addition to the file.
+	FormData map[string]string
+}
+
+// fileLinkValues returns the query parameters of a presigned download link, overriding the defaults of the client.
+func fileLinkValues(defaults url.Values, options []FileLinkOption) url.Values {
+	opts := new(fileLinkOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	values := maps.Clone(defaults)
+	if values == nil {
+		values = make(url.Values)
+	}
+
+	if opts.disposition != "" {
+		values.Set(queryContentDisposition, contentDisposition(opts.disposition, opts.fileName))
+	}
+
+	if opts.contentType != "" {
+		values.Set(queryContentType, opts.contentType)
+	}
+
+	if opts.cacheControl != "" {
+		values.Set(queryCacheControl, opts.cacheControl)
+	}
+
+	return values
+}
+
+// contentDisposition returns a Content-Disposition header with the given file name. Names which are not
+// plain ASCII are encoded according to RFC 5987, along with an ASCII fallback for older browsers.
+func contentDisposition(disposition, fileName string) string {
+	if fileName == "" {
+		return disposition
+	}
+
+	fallback := strings.Map(func(r rune) rune {
+		if r < ' ' || r > '~' || r == '"' || r == '\\' {
+			return '_'
+		}
+
+		return r
+	}, fileName)
+
+	if fallback == fileName {
+		return fmt.Sprintf(`%s; filename="%s"`, disposition, fileName)
+	}
+
+	return fmt.Sprintf(`%s; filename="%s"; filename*=UTF-8''%s`, disposition, fallback, encodeRFC5987(fileName))
+}
+
+// encodeRFC5987 percent encodes all bytes of the value except the attr-chars of RFC 5987.
+func encodeRFC5987(value string) string {
+	const attrChars = "!#$&+-.^_`|~"
+
+	var encoded strings.Builder
+
+	for _, b := range []byte(value) {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9', strings.IndexByte(attrChars, b) >= 0:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
+
+func newUploadLinkOptions(options []UploadLinkOption) (*uploadLinkOptions, error) {
+	opts := new(uploadLinkOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	for _, algorithm := range slices.Concat(slices.Collect(maps.Keys(opts.checksums)), opts.requiredSums) {
+		if _, err := lookupChecksumAlgorithm(algorithm); err != nil {
+			return nil, err
+		}
+	}
+
+	return opts, nil
+}
+
+// userMetaData returns the required metadata including the required checksums, with lower case keys.
+func (o *uploadLinkOptions) userMetaData() map[string]string {
+	metaData := make(map[string]string, len(o.metaData)+len(o.checksums))
+
+	for key, value := range o.metaData {
+		metaData[strings.ToLower(key)] = value
+	}
+
+	for key, value := range checksumMetaData(o.checksums) {
+		metaData[strings.ToLower(key)] = value
+	}
+
+	return metaData
+}
+
+// requiredMetaDataKeys returns the lower case metadata keys the upload form has to contain with any value.
+func (o *uploadLinkOptions) requiredMetaDataKeys() []string {
+	keys := make([]string, 0, len(o.requiredMetaData)+len(o.requiredSums))
+
+	for _, key := range o.requiredMetaData {
+		keys = append(keys, strings.ToLower(key))
+	}
+
+	for _, algorithm := range o.requiredSums {
+		definition, _ := lookupChecksumAlgorithm(algorithm) //nolint:errcheck // validated by newUploadLinkOptions
+
+		keys = append(keys, strings.ToLower(definition.metaDataKey))
+	}
+
+	return keys
+}
+
+// uploadLinkHeaders returns the headers which are signed as part of a presigned upload link.
+// Conditions which can only be expressed by upload policies are rejected.
+func (o *uploadLinkOptions) uploadLinkHeaders() (http.Header, error) {
+	const errMessage = "%w: %s can only be required by upload policies"
+
+	switch {
+	case o.pathPrefix:
+		return nil, fmt.Errorf(errMessage, ErrNotSupported, "a path prefix")
+	case o.minSize != 0 || o.maxSize != 0:
+		return nil, fmt.Errorf(errMessage, ErrNotSupported, "a content length range")
+	case len(o.requiredMetaData) > 0 || len(o.requiredSums) > 0:
+		return nil, fmt.Errorf(errMessage, ErrNotSupported, "metadata without value")
+	}
+
+	metaData := o.userMetaData()
+	headers := make(http.Header, len(metaData)+1)
+
+	if o.contentType != "" {
+		headers.Set("Content-Type", o.contentType)
+	}
+
+	for key, value := range metaData {
+		headers.Set(metaDataHeaderPrefix+key, value)
+	}
+
+	return headers, nil
+}
+
+// uploadPolicyFormData returns the form data of an upload policy for stores, which do not sign policies.
+func (o *uploadLinkOptions) uploadPolicyFormData(path string) map[string]string {
+	formData := map[string]string{"key": path}
+
+	if o.contentType != "" {
+		formData["Content-Type"] = o.contentType
+	}
+
+	for _, key := range o.requiredMetaDataKeys() {
+		formData[metaDataHeaderPrefix+key] = ""
+	}
+
+	for key, value := range o.userMetaData() {
+		formData[metaDataHeaderPrefix+key] = value
+	}
+
+	return formData
+}
+
+// postPolicy returns the policy of a browser upload to the given bucket.
+func (o *uploadLinkOptions) postPolicy(bucketName, path string, expiration time.Duration) (*minio.PostPolicy, error) {
+	policy := minio.NewPostPolicy()
+
+	if err := policy.SetBucket(bucketName); err != nil {
+		return nil, err
+	}
+
+	setKey := policy.SetKey
+	if o.pathPrefix {
+		setKey = policy.SetKeyStartsWith
+	}
+
+	if err := setKey(path); err != nil {
+		return nil, err
+	}
+
+	if err := policy.SetExpires(time.Now().UTC().Add(expiration)); err != nil {
+		return nil, err
+	}
+
+	if o.contentType != "" {
+		if err := policy.SetContentType(o.contentType); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.minSize != 0 || o.maxSize != 0 {
+		if err := policy.SetContentLengthRange(o.minSize, o.maxSize); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range o.requiredMetaDataKeys() {
+		if err := policy.SetUserMetadataStartsWith(key, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	for key, value := range o.userMetaData() {
+		if err := policy.SetUserMetadata(key, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,431 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+const (
+	defaultRetryAttempts       = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+)
+
+// retryableCodes are the s3 error codes of temporary server failures, besides throttling and unavailability.
+var retryableCodes = map[string]struct{}{
+	"InternalError":  {},
+	"RequestTimeout": {},
+}
+
+// RetryPolicy controls how failed requests of the s3 backed client are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, 1 disables retries.
+	MaxAttempts int
+	// InitialBackoff is the upper bound of the delay before the first retry, it doubles with every retry.
+	// The delay is chosen randomly up to the bound (full jitter), so clients do not retry in lockstep.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the upper bound of the delay, 0 does not cap it.
+	MaxBackoff time.Duration
+	// AttemptTimeout limits the duration of a single attempt, 0 does not limit it.
+	// Attempts of GetFile end once the file has been opened, since its content is read by the caller.
+	// Listings are not limited, since their pages are streamed.
+	AttemptTimeout time.Duration
+	// Retryable reports whether the failed attempt of the operation is retried, nil uses IsRetryable.
+	Retryable func(operation Operation, err error) bool
+	// OnRetry is called before every retry, e.g. to log the failed attempt.
+	OnRetry func(attempt *RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt which is retried.
+type RetryAttempt struct {
+	Operation Operation
+	// Key is the path of the file, or the prefix of listings. It is empty for operations on the bucket.
+	Key string
+	// Attempt is the number of the failed attempt, starting at 1.
+	Attempt int
+	Err     error
+	// Delay is the time waited before the next attempt.
+	Delay time.Duration
+}
+
+// DefaultRetryPolicy returns a policy of 3 attempts with a backoff from 100ms up to 5s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    defaultRetryAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+}
+
+// IsRetryable reports whether the error is temporary: server errors, throttling, timeouts of single attempts
+// and connection failures. Checksum mismatches are retried by DownloadFile, since a new attempt downloads
+// the content again.
+func IsRetryable(operation Operation, err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	if errors.Is(err, ErrChecksumMismatch) {
+		return operation == OperationDownloadFile
+	}
+
+	var minioResponse minio.ErrorResponse
+
+	if errors.As(err, &minioResponse) {
+		if kind := classifyResponse(&minioResponse); errors.Is(kind, ErrThrottled) || errors.Is(kind, ErrServiceUnavailable) {
+			return true
+		}
+
+		_, ok := retryableCodes[minioResponse.Code]
+
+		return ok || minioResponse.StatusCode >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
+
+func (p *RetryPolicy) validate() error {
+	if p == nil || p.MaxAttempts < 1 || p.InitialBackoff < 0 || p.MaxBackoff < 0 || p.AttemptTimeout < 0 {
+		return ErrInvalidRetryPolicy
+	}
+
+	return nil
+}
+
+// backoff returns the random delay before the retry following the given attempt.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	bound := p.InitialBackoff
+
+	for range attempt - 1 {
+		if p.MaxBackoff > 0 && bound >= p.MaxBackoff {
+			break
+		}
+
+		bound *= 2
+	}
+
+	if p.MaxBackoff > 0 {
+		bound = min(bound, p.MaxBackoff)
+	}
+
+	if bound <= 0 {
+		return 0
+	}
+
+	return rand.N(bound + 1) //nolint:gosec // jitter does not need a secure random number
+}
+
+// shouldRetry reports whether the failed attempt is retried and waits for the backoff.
+// It returns false once the attempts are used up or the context is done.
+func (p *RetryPolicy) shouldRetry(ctx context.Context, operation Operation, key string, attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || !p.retryable(operation, err) {
+		return false
+	}
+
+	delay := p.backoff(attempt)
+
+	if p.OnRetry != nil {
+		p.OnRetry(&RetryAttempt{Operation: operation, Key: key, Attempt: attempt, Err: err, Delay: delay})
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// retryable reports whether the error of the operation is retried by the policy, ignoring its attempts.
+func (p *RetryPolicy) retryable(operation Operation, err error) bool {
+	if p == nil {
+		return false
+	}
+
+	if p.Retryable == nil {
+		return IsRetryable(operation, err)
+	}
+
+	return p.Retryable(operation, err)
+}
+
+// attemptContext returns the context of a single attempt, limited by the attempt timeout.
+// The timeout only applies until stop is called, the context is released by cancel.
+func (p *RetryPolicy) attemptContext(ctx context.Context) (attemptCtx context.Context, cancel, stop func()) {
+	attemptCtx, cancelCause := context.WithCancelCause(ctx)
+	cancel = func() { cancelCause(nil) }
+
+	if p == nil || p.AttemptTimeout == 0 {
+		return attemptCtx, cancel, func() {}
+	}
+
+	timer := time.AfterFunc(p.AttemptTimeout, func() { cancelCause(context.DeadlineExceeded) })
+
+	return attemptCtx, cancel, func() { timer.Stop() }
+}
+
+// retry runs the attempt until it succeeds, fails with an error which is not retryable or the attempts are used up.
+// The last error is returned. Without retry policy the attempt runs once.
+func retry[T any](
+	ctx context.Context,
+	policy *RetryPolicy,
+	operation Operation,
+	key string,
+	attempt func(ctx context.Context) (T, error),
+) (T, error) {
+	return retryOpen(ctx, policy, operation, key, func(ctx context.Context, cancel func()) (T, error) {
+		defer cancel()
+
+		return attempt(ctx)
+	})
+}
+
+// retryCall retries the attempt like retry, for attempts which only return an error.
+func retryCall(
+	ctx context.Context,
+	policy *RetryPolicy,
+	operation Operation,
+	key string,
+	attempt func(ctx context.Context) error,
+) error {
+	_, err := retry(ctx, policy, operation, key, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, attempt(ctx)
+	})
+
+	return err
+}
+
+// retryOpen retries the attempt like retry. The context of a successful attempt stays valid until
+// the attempt calls cancel, so content opened by the attempt can be read after it returned.
+func retryOpen[T any](
+	ctx context.Context,
+	policy *RetryPolicy,
+	operation Operation,
+	key string,
+	attempt func(ctx context.Context, cancel func()) (T, error),
+) (T, error) {
+	for number := 1; ; number++ {
+		attemptCtx, cancel, stop := policy.attemptContext(ctx)
+
+		result, err := attempt(attemptCtx, cancel)
+
+		stop()
+
+		if err == nil {
+			return result, nil
+		}
+
+		var requestErr *RequestError
+
+		// s3 does not return the key in the responses of all requests
+		if err = handleClientError(err); errors.As(err, &requestErr) && requestErr.Key == "" {
+			requestErr.Key = key
+		}
+
+		// requests cancelled by the attempt timeout fail with context.Canceled
+		if ctx.Err() == nil && errors.Is(context.Cause(attemptCtx), context.DeadlineExceeded) {
+			err = fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+		}
+
+		cancel()
+
+		if !policy.shouldRetry(ctx, operation, key, number, err) {
+			return result, err
+		}
+	}
+}
+
+// releasingReader releases the context of the request reading the content once the content is closed.
+type releasingReader struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close implements the io.Closer interface.
+func (r *releasingReader) Close() error {
+	defer r.release()
+
+	return r.ReadCloser.Close()
+}
+
+// listObjects lists the objects, retrying failed pages according to the retry policy. Listings of objects
+// resume after the last listed object and skip the folders already listed. Listings of versions restart and skip
+// the versions already listed. Both are ordered by key.
+func (c *client) listObjects(ctx context.Context, opts minio.ListObjectsOptions) iter.Seq2[minio.ObjectInfo, error] {
+	return func(yield func(minio.ObjectInfo, error) bool) {
+		var lastKey string
+
+		// versions of the last key which have been listed
+		listed := make(map[string]struct{})
+
+		for number := 1; ; number++ {
+			err := c.listObjectsAttempt(ctx, opts, func(objInfo minio.ObjectInfo) bool {
+				if opts.WithVersions {
+					if _, ok := listed[objInfo.VersionID]; objInfo.Key < lastKey || (objInfo.Key == lastKey && ok) {
+						return true
+					}
+
+					if objInfo.Key != lastKey {
+						lastKey = objInfo.Key
+
+						clear(listed)
+					}
+
+					listed[objInfo.VersionID] = struct{}{}
+				} else {
+					if objInfo.Key <= lastKey {
+						return true
+					}
+
+					lastKey = objInfo.Key
+
+					// listings which are not recursive return the common prefixes of folders, which s3 would
+					// list again after their first object, so they are not resumed after
+					if opts.Recursive || !strings.HasSuffix(objInfo.Key, "/") {
+						opts.StartAfter = objInfo.Key
+					}
+				}
+
+				// a listing making progress starts counting its attempts again
+				number = 1
+
+				return yield(objInfo, nil)
+			})
+			if err != nil {
+				err = handleClientError(err)
+			}
+
+			if err == nil || !c.retryPolicy.shouldRetry(ctx, OperationListFiles, opts.Prefix, number, err) {
+				if err != nil {
+					yield(minio.ObjectInfo{}, err)
+				}
+
+				return
+			}
+		}
+	}
+}
+
+// listObjectsAttempt lists the objects once. It returns nil once the listing completed or the caller stopped it.
+func (c *client) listObjectsAttempt(ctx context.Context, opts minio.ListObjectsOptions, yield func(minio.ObjectInfo) bool) error {
+	// stops the listing when the caller stops the iteration early
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	for objInfo := range c.minioClient.ListObjects(ctx, c.bucketName, opts) {
+		if objInfo.Err != nil {
+			return objInfo.Err
+		}
+
+		if !yield(objInfo) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// removeObjects removes the objects using multi-object deletes of up to 1000 keys. The keys of a batch which failed
+// with a retryable error are removed again according to the retry policy, the results of all other keys are returned.
+func (c *client) removeObjects(ctx context.Context, objects iter.Seq[minio.ObjectInfo]) iter.Seq[minio.RemoveObjectResult] {
+	return func(yield func(minio.RemoveObjectResult) bool) {
+		batch := make([]minio.ObjectInfo, 0, maxRemoveBatchSize)
+
+		for objInfo := range objects {
+			batch = append(batch, objInfo)
+
+			if len(batch) < maxRemoveBatchSize {
+				continue
+			}
+
+			if !c.removeBatch(ctx, batch, yield) {
+				return
+			}
+
+			batch = batch[:0]
+		}
+
+		if len(batch) > 0 {
+			c.removeBatch(ctx, batch, yield)
+		}
+	}
+}
+
+// removeBatch removes a batch of objects, retrying the keys which failed with a retryable error.
+// It returns false once the caller stopped the iteration or the removal failed as a whole.
+func (c *client) removeBatch(ctx context.Context, batch []minio.ObjectInfo, yield func(minio.RemoveObjectResult) bool) bool {
+	for number := 1; ; number++ {
+		removed, err := c.minioClient.RemoveObjectsWithIter(ctx, c.bucketName, slices.Values(batch), minio.RemoveObjectsOptions{})
+		if err != nil {
+			yield(minio.RemoveObjectResult{Err: err})
+
+			return false
+		}
+
+		var failures []minio.RemoveObjectResult
+
+		for result := range removed {
+			// the response could not be read, so it is unknown which keys have been removed
+			if result.ObjectName == "" {
+				yield(result)
+
+				return false
+			}
+
+			if result.Err != nil && c.retryPolicy.retryable(OperationRemoveFile, handleClientError(result.Err)) {
+				failures = append(failures, result)
+
+				continue
+			}
+
+			if !yield(result) {
+				return false
+			}
+		}
+
+		if len(failures) == 0 {
+			return true
+		}
+
+		// removing keys again is harmless, so the failed keys are retried as a new batch
+		if c.retryPolicy.shouldRetry(ctx, OperationRemoveFile, failures[0].ObjectName, number, handleClientError(failures[0].Err)) {
+			batch = make([]minio.ObjectInfo, 0, len(failures))
+
+			for _, failure := range failures {
+				batch = append(batch, minio.ObjectInfo{Key: failure.ObjectName, VersionID: failure.ObjectVersionID})
+			}
+
+			continue
+		}
+
+		for _, failure := range failures {
+			if !yield(failure) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
@@ -0,0 +1,271 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid policy", func(t *testing.T) {
+		t.Parallel()
+
+		clientDetails := &s3.ClientDetails{Host: "localhost:9000", AccessKey: "key", AccessSecret: "secret", BucketName: "test-bucket"}
+
+		for _, policy := range []*s3.RetryPolicy{
+			nil,
+			{MaxAttempts: 0},
+			{MaxAttempts: 3, InitialBackoff: -time.Second},
+			{MaxAttempts: 3, AttemptTimeout: -time.Second},
+		} {
+			_, err := s3.NewClient(clientDetails, s3.WithRetryPolicy(policy))
+			require.ErrorIs(t, err, s3.ErrInvalidRetryPolicy)
+		}
+	})
+
+	t.Run("classification", func(t *testing.T) {
+		t.Parallel()
+
+		tests := map[string]struct {
+			operation s3.Operation
+			err       error
+			retryable bool
+		}{
+			"throttling": {
+				operation: s3.OperationUploadFile,
+				err:       minio.ErrorResponse{Code: "SlowDown", StatusCode: http.StatusServiceUnavailable},
+				retryable: true,
+			},
+			"server error": {
+				operation: s3.OperationGetFileInfo,
+				err:       minio.ErrorResponse{Code: "Unknown", StatusCode: http.StatusBadGateway},
+				retryable: true,
+			},
+			"not found": {
+				operation: s3.OperationGetFile,
+				err:       minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound},
+				retryable: false,
+			},
+			"attempt timeout": {
+				operation: s3.OperationListFiles,
+				err:       context.DeadlineExceeded,
+				retryable: true,
+			},
+			"cancelled": {
+				operation: s3.OperationListFiles,
+				err:       context.Canceled,
+				retryable: false,
+			},
+			"checksum mismatch of download": {
+				operation: s3.OperationDownloadFile,
+				err:       s3.ErrChecksumMismatch,
+				retryable: true,
+			},
+			"checksum mismatch of upload": {
+				operation: s3.OperationUploadFile,
+				err:       s3.ErrChecksumMismatch,
+				retryable: false,
+			},
+			"other error": {
+				operation: s3.OperationRemoveFile,
+				err:       errors.New("failed"),
+				retryable: false,
+			},
+		}
+
+		for name, test := range tests {
+			require.Equal(t, test.retryable, s3.IsRetryable(test.operation, test.err), name)
+		}
+	})
+}
+
+func Test_Retries(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	folder := "test-retries/" + uuid.NewString()
+
+	t.Run("succeeding requests", func(t *testing.T) {
+		t.Parallel()
+
+		var retries atomic.Int32
+
+		policy := s3.DefaultRetryPolicy()
+		policy.OnRetry = func(*s3.RetryAttempt) { retries.Add(1) }
+
+		s3Client := getS3Client(t, s3.WithRetryPolicy(policy))
+
+		content := []byte("retried content")
+		size := int64(len(content))
+		filePath := folder + "/succeeding/file.txt"
+
+		_, err := s3Client.UploadFile(ctx, s3.NewUpload(bytes.NewReader(content), &size, filePath, contentType, nil))
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(ctx, filePath)
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, content, fileContent)
+
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+
+		err = s3Client.DownloadFile(ctx, filePath, localPath)
+		require.NoError(t, err)
+
+		downloaded, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		require.Equal(t, content, downloaded)
+
+		paths := make([]string, 0)
+
+		for info, err := range s3Client.ListFiles(ctx, folder+"/succeeding/") {
+			require.NoError(t, err)
+
+			paths = append(paths, info.Path)
+		}
+
+		require.Equal(t, []string{filePath}, paths)
+
+		removed, err := s3Client.RemoveDirectory(ctx, folder+"/succeeding")
+		require.NoError(t, err)
+		require.Equal(t, []string{filePath}, removed)
+		require.Zero(t, retries.Load())
+	})
+
+	t.Run("failing requests", func(t *testing.T) {
+		t.Parallel()
+
+		attempts := make([]*s3.RetryAttempt, 0)
+
+		s3Client := getS3Client(t, s3.WithRetryPolicy(&s3.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Millisecond,
+			Retryable:      func(s3.Operation, error) bool { return true },
+			OnRetry:        func(attempt *s3.RetryAttempt) { attempts = append(attempts, attempt) },
+		}))
+
+		filePath := folder + "/failing/missing.txt"
+
+		_, err := s3Client.GetFileInfo(ctx, filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+		require.Len(t, attempts, 2)
+
+		for i, attempt := range attempts {
+			require.Equal(t, s3.OperationGetFileInfo, attempt.Operation)
+			require.Equal(t, filePath, attempt.Key)
+			require.Equal(t, i+1, attempt.Attempt)
+			require.LessOrEqual(t, attempt.Delay, time.Millisecond<<i)
+		}
+	})
+
+	t.Run("corrupted download", func(t *testing.T) {
+		t.Parallel()
+
+		content := []byte("downloaded content")
+		filePath := folder + "/corrupted/file.txt"
+
+		target, err := url.Parse("http://" + s3URL)
+		require.NoError(t, err)
+
+		var corrupted atomic.Bool
+
+		// the proxy corrupts the content of the first download on its way to the client
+		proxy := httputil.NewSingleHostReverseProxy(target)
+		proxy.ModifyResponse = func(resp *http.Response) error {
+			if resp.Request.Method == http.MethodGet && strings.HasSuffix(resp.Request.URL.Path, filePath) &&
+				corrupted.CompareAndSwap(false, true) {
+				resp.Body = &corruptingReader{ReadCloser: resp.Body}
+			}
+
+			return nil
+		}
+
+		server := httptest.NewServer(proxy)
+		t.Cleanup(server.Close)
+
+		attempts := make([]*s3.RetryAttempt, 0)
+
+		policy := s3.DefaultRetryPolicy()
+		policy.OnRetry = func(attempt *s3.RetryAttempt) { attempts = append(attempts, attempt) }
+
+		s3Client, err := s3.NewClient(&s3.ClientDetails{
+			Host:         strings.TrimPrefix(server.URL, "http://"),
+			AccessKey:    s3User,
+			AccessSecret: s3Pwd,
+			BucketName:   bucketName,
+		}, s3.WithRetryPolicy(policy))
+		require.NoError(t, err)
+
+		t.Cleanup(s3Client.Close)
+
+		_, err = s3Client.UploadFile(ctx, s3.NewUpload(bytes.NewReader(content), nil, filePath, contentType, nil))
+		require.NoError(t, err)
+
+		localPath := filepath.Join(t.TempDir(), "file.txt")
+
+		err = s3Client.DownloadFile(ctx, filePath, localPath)
+		require.NoError(t, err)
+
+		downloaded, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+		require.Equal(t, content, downloaded)
+
+		require.True(t, corrupted.Load())
+		require.Len(t, attempts, 1)
+		require.Equal(t, s3.OperationDownloadFile, attempts[0].Operation)
+		require.ErrorIs(t, attempts[0].Err, s3.ErrChecksumMismatch)
+	})
+
+	t.Run("attempt timeout", func(t *testing.T) {
+		t.Parallel()
+
+		var retries atomic.Int32
+
+		s3Client := getS3Client(t, s3.WithRetryPolicy(&s3.RetryPolicy{
+			MaxAttempts:    2,
+			AttemptTimeout: time.Nanosecond,
+			OnRetry:        func(*s3.RetryAttempt) { retries.Add(1) },
+		}))
+
+		_, err := s3Client.GetFileInfo(ctx, folder+"/timeout/file.txt")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, int32(1), retries.Load())
+	})
+}
+
+// corruptingReader flips a bit of the content it reads first.
+type corruptingReader struct {
+	io.ReadCloser
+	done bool
+}
+
+func (r *corruptingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	if n > 0 && !r.done {
+		p[0] ^= 0x01
+		r.done = true
+	}
+
+	return n, err
+}
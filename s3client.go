@@ -2,6 +2,7 @@ package s3 //nolint:revive // package name matches folder name
 
 import (
 	"context"
+	"iter"
 	"net/url"
 	"time"
 )
@@ -13,33 +14,139 @@ type Client interface {
 	// UploadFile uploads data under a given s3 path.
 	UploadFile(ctx context.Context, upload *Upload, options ...UploadOption) (*UploadInfo, error)
 
+	// UploadStream uploads data of unknown size from a non-seekable reader under a given s3 path using a multipart upload.
+	UploadStream(ctx context.Context, upload *StreamUpload, options ...UploadOption) (*UploadInfo, error)
+
 	// GetFile returns the file from given s3 path.
 	GetFile(ctx context.Context, path string, options ...GetOption) (File, error)
 
 	// GetObjectInfo returns an minio.ObjectInfo for the given s3 path.
-	GetFileInfo(ctx context.Context, path string) (*FileInfo, error)
+	GetFileInfo(ctx context.Context, path string, options ...FileInfoOption) (*FileInfo, error)
 
-	// GetDirectory returns a list of files from given s3 folder.
+	// GetDirectory returns a list of files from given s3 folder, sorted by path.
+	// If some of the files failed, a BulkError describing the failed keys is returned.
 	GetDirectory(ctx context.Context, path string, options ...GetDirectoryOption) ([]File, error)
 
-	// GetDirectoryInfos returns a list of file infos for all files from given s3 folder.
-	GetDirectoryInfos(ctx context.Context, path string) ([]*FileInfo, error)
+	// GetDirectoryInfos returns a list of file infos for all files from given s3 folder, sorted by path.
+	// If some of the files failed, a BulkError describing the failed keys is returned.
+	GetDirectoryInfos(ctx context.Context, path string, options ...GetDirectoryOption) ([]*FileInfo, error)
+
+	// ListFiles returns an iterator over the file infos of all files under the given s3 prefix, ordered by key.
+	// The files are requested page by page, so only a single page is held in memory.
+	ListFiles(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileInfo, error]
+
+	// ListFilesPage returns a single page of files under the given s3 prefix and a continuation token for the next page.
+	ListFilesPage(ctx context.Context, prefix string, options ...ListOption) (*FilePage, error)
+
+	// UploadDirectory uploads the files of the local folder under the given s3 prefix, keeping the folder structure.
+	// The uploaded files are returned sorted by path. If some of the files failed, a BulkError describing
+	// the failed keys is returned along with the uploaded files.
+	UploadDirectory(ctx context.Context, localPath, prefix string, options ...UploadDirectoryOption) ([]*UploadedFile, error)
+
+	// PlanSync compares the local folder with the s3 prefix and returns the changes required to make the destination
+	// of the given direction match its source, without applying them.
+	PlanSync(ctx context.Context, localPath, prefix string, direction SyncDirection, options ...SyncOption) (*SyncPlan, error)
+
+	// ApplySync applies the changes of the plan. If some of the files failed, a BulkError describing the failed keys is returned.
+	ApplySync(ctx context.Context, plan *SyncPlan) error
 
 	// DownloadFile downloads the requested file to the file system under given localPath.
 	DownloadFile(ctx context.Context, path, localPath string, options ...DownloadOption) error
 
 	// DownloadDirectory downloads the requested folder to the file system.
 	// The recursive option also downloads all sub folders.
+	// If some of the files failed, a BulkError describing the failed keys is returned.
 	DownloadDirectory(ctx context.Context, path, localPath string, recursive bool, options ...DownloadOption) error
 
 	// RemoveFile deletes the file under given s3 path.
 	RemoveFile(ctx context.Context, path string, options ...RemoveOption) error
 
-	// AddLifeCycleRule adds a lifecycle rule to the given folder.
+	// RemoveDirectory deletes all files under the given s3 folder using batched deletes and returns the removed paths, sorted by path.
+	// If some of the files could not be removed, a BulkError describing the failed keys is returned along with the removed paths.
+	RemoveDirectory(ctx context.Context, path string, options ...RemoveDirectoryOption) ([]string, error)
+
+	// CopyFile copies the file under srcPath to dstPath server-side, keeping its metadata, content type and checksums.
+	// Files larger than 5 GiB are copied using a multipart copy.
+	CopyFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error)
+
+	// MoveFile copies the file under srcPath to dstPath server-side and deletes the source file once the copy succeeded.
+	MoveFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error)
+
+	// EnableVersioning enables versioning of the bucket, so all versions of the files are kept.
+	EnableVersioning(ctx context.Context) error
+
+	// SuspendVersioning suspends versioning of the bucket. Existing versions are kept.
+	SuspendVersioning(ctx context.Context) error
+
+	// GetVersioningStatus returns the versioning status of the bucket.
+	GetVersioningStatus(ctx context.Context) (VersioningStatus, error)
+
+	// ListFileVersions returns an iterator over all versions and delete markers of the files under the given s3 prefix,
+	// ordered by key and from the newest to the oldest version. WithStartAfter and WithContinuationToken are not supported.
+	ListFileVersions(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileVersion, error]
+
+	// RestoreFileVersion makes the given version the current version of the file using a server-side copy.
+	// All versions are kept, the restored version becomes the latest version.
+	RestoreFileVersion(ctx context.Context, path, versionID string) (*UploadInfo, error)
+
+	// RotateFileKey re-wraps the data key of the client-side encrypted file with the current key of the key provider
+	// using a server-side copy, so the content is neither downloaded nor re-encrypted.
+	// Files already wrapped by the current key are left untouched.
+	RotateFileKey(ctx context.Context, path string) error
+
+	// SetRetention sets the retention of the file, a nil retention removes it.
+	// Retentions in governance mode can only be shortened or removed using WithGovernanceBypass.
+	SetRetention(ctx context.Context, path string, retention *Retention, options ...LockOption) error
+
+	// GetRetention returns the retention of the file, or nil if the file has no retention.
+	GetRetention(ctx context.Context, path string, options ...LockOption) (*Retention, error)
+
+	// SetLegalHold enables or disables the legal hold of the file.
+	SetLegalHold(ctx context.Context, path string, enabled bool, options ...LockOption) error
+
+	// GetLegalHold reports whether the file has a legal hold.
+	GetLegalHold(ctx context.Context, path string, options ...LockOption) (bool, error)
+
+	// GetObjectLockConfig returns the object lock configuration of the bucket including the default retention.
+	GetObjectLockConfig(ctx context.Context) (*ObjectLockConfig, error)
+
+	// GetTags returns the tags of the file.
+	GetTags(ctx context.Context, path string, options ...TagOption) (map[string]string, error)
+
+	// PutTags replaces all tags of the file with the given tags.
+	PutTags(ctx context.Context, path string, tags map[string]string, options ...TagOption) error
+
+	// RemoveTags removes all tags of the file.
+	RemoveTags(ctx context.Context, path string, options ...TagOption) error
+
+	// AddLifeCycleRule adds a lifecycle rule expiring the files of the given folder.
+	// An existing rule with the same id is replaced, all other rules are kept.
 	AddLifeCycleRule(ctx context.Context, ruleID, folderPath string, daysToExpiry int) error
 
+	// GetLifecycleRules returns all lifecycle rules of the bucket.
+	GetLifecycleRules(ctx context.Context) ([]*LifecycleRule, error)
+
+	// GetLifecycleRule returns the lifecycle rule with the given id.
+	GetLifecycleRule(ctx context.Context, ruleID string) (*LifecycleRule, error)
+
+	// PutLifecycleRule adds the lifecycle rule or replaces the rule with the same id, all other rules are kept.
+	PutLifecycleRule(ctx context.Context, rule *LifecycleRule) error
+
+	// RemoveLifecycleRule removes the lifecycle rule with the given id, all other rules are kept.
+	RemoveLifecycleRule(ctx context.Context, ruleID string) error
+
 	// CreateFileLink creates a link with expiration for a file under the given path.
-	CreateFileLink(ctx context.Context, path string, expiration time.Duration) (*url.URL, error)
+	// By default browsers display the file, the options allow downloading it under a given name.
+	// Files encrypted with SSE-C cannot be downloaded using links, since the key has to be sent in the request headers.
+	CreateFileLink(ctx context.Context, path string, expiration time.Duration, options ...FileLinkOption) (*url.URL, error)
+
+	// CreateUploadLink creates a presigned link with expiration to upload a file under the given path using a PUT request.
+	// The returned headers are part of the signature and have to be sent with the upload.
+	CreateUploadLink(ctx context.Context, path string, expiration time.Duration, options ...UploadLinkOption) (*UploadLink, error)
+
+	// CreateUploadPolicy creates a presigned policy with expiration to upload a file under the given path from a browser
+	// using a POST form. The returned form data has to be sent along with the file.
+	CreateUploadPolicy(ctx context.Context, path string, expiration time.Duration, options ...UploadLinkOption) (*UploadPolicy, error)
 
 	// Close closes the s3 client.
 	Close()
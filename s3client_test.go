@@ -5,9 +5,15 @@ import (
 	"context"
 	"embed"
 	"io"
+	"maps"
+	"mime/multipart"
 	"net/http"
 	"os"
+	pathpkg "path"
+	"path/filepath"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +36,10 @@ const (
 
 	contentType    = "text/plain"
 	testDataFolder = "testdata"
+
+	// testKMSKeyID is the key of the static key management service of the container, enabling SSE-S3 and SSE-KMS.
+	testKMSKeyID  = "test-key"
+	testKMSSecret = testKMSKeyID + ":j+2bu2YvMWK4/AyKW4zgPXwTeJDolGj8dbJGXPqn4g0="
 )
 
 //go:embed testdata
@@ -381,6 +391,90 @@ func Test_GetDirectory(t *testing.T) {
 	})
 }
 
+func Test_BoundedConcurrency(t *testing.T) {
+	t.Parallel()
+
+	testBoundedConcurrency(t, getS3Client, "test-bounded-concurrency")
+}
+
+func testBoundedConcurrency(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
+
+	s3Client := newClient(t, s3.WithConcurrency(2))
+
+	paths := make([]string, 0, 6)
+
+	for range 6 {
+		paths = append(paths, uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name).filePath)
+	}
+
+	slices.Sort(paths)
+
+	t.Run("get directory sorted by path", func(t *testing.T) {
+		t.Parallel()
+
+		files, err := s3Client.GetDirectory(context.Background(), s3Folder, s3.WithGetDirectoryConcurrency(3))
+		require.NoError(t, err)
+		require.Len(t, files, len(paths))
+
+		for i := range files {
+			require.Equal(t, paths[i], files[i].Info().Path)
+			require.NoError(t, files[i].Close())
+		}
+	})
+
+	t.Run("get directory infos sorted by path", func(t *testing.T) {
+		t.Parallel()
+
+		fileInfos, err := s3Client.GetDirectoryInfos(context.Background(), s3Folder)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, len(paths))
+
+		for i := range fileInfos {
+			require.Equal(t, paths[i], fileInfos[i].Path)
+		}
+	})
+
+	t.Run("download directory fails fast", func(t *testing.T) {
+		t.Parallel()
+
+		// a file blocks the creation of the download folder, so every download fails
+		localPath := filepath.Join(t.TempDir(), "blocked")
+
+		err := os.WriteFile(localPath, nil, 0o600)
+		require.NoError(t, err)
+
+		err = s3Client.DownloadDirectory(context.Background(), s3Folder, localPath+"/folder", true,
+			s3.WithDownloadConcurrency(1), s3.WithDownloadFailFast(),
+		)
+
+		var bulkErr *s3.BulkError
+
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Failures, 1)
+		require.Empty(t, bulkErr.Succeeded)
+		require.Equal(t, s3.OperationDownloadFile, bulkErr.Failures[0].Operation)
+		require.Contains(t, paths, bulkErr.Failures[0].Key)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := s3Client.GetDirectoryInfos(ctx, s3Folder)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("invalid concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewMemoryClient(bucketName, s3.WithConcurrency(0))
+		require.ErrorIs(t, err, s3.ErrInvalidConcurrency)
+	})
+}
+
 func Test_GetDirectoryInfos(t *testing.T) {
 	t.Parallel()
 
@@ -415,214 +509,1212 @@ func Test_GetDirectoryInfos(t *testing.T) {
 	}
 }
 
-func Test_DownloadFile(t *testing.T) {
+func Test_ListFiles(t *testing.T) {
 	t.Parallel()
 
-	const (
-		folder      = "test-download-file"
-		localFolder = "testdata/temp-download-file"
-	)
+	testListFiles(t, getS3Client, "test-list-files")
+}
 
-	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+func testListFiles(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
 
-	t.Cleanup(func() {
-		err := os.RemoveAll(localFolder)
-		require.NoError(t, err)
+	s3Client := newClient(t)
+
+	prefix := s3Folder + "/"
+
+	uploads := make([]*uploaded, 0, 5)
+
+	for range 5 {
+		uploads = append(uploads, uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name))
+	}
+
+	slices.SortFunc(uploads, func(a, b *uploaded) int {
+		return strings.Compare(a.filePath, b.filePath)
 	})
 
-	t.Run("download file", func(t *testing.T) {
+	t.Run("list files", func(t *testing.T) {
 		t.Parallel()
 
-		uploaded := uploadTestFile(t, folder, testFile1Name)
+		paths := make([]string, 0, len(uploads))
 
-		localPath := localFolder + "/" + uploaded.fileName
+		for info, err := range s3Client.ListFiles(context.Background(), prefix, s3.WithPageSize(2)) {
+			require.NoError(t, err)
 
-		err := s3Client.DownloadFile(context.Background(), uploaded.filePath, localPath)
-		require.NoError(t, err)
+			require.Equal(t, uploads[0].lenTestFile, info.Size)
+			require.Equal(t, pathpkg.Base(info.Path), info.Name)
+			require.False(t, info.ModifiedDate.IsZero())
+			require.Empty(t, info.MetaData)
 
-		fileBytes, err := os.ReadFile(localPath)
-		require.NoError(t, err)
+			paths = append(paths, info.Path)
+		}
 
-		require.Equal(t, uploaded.content, fileBytes)
+		require.Len(t, paths, len(uploads))
+
+		for i := range uploads {
+			require.Equal(t, uploads[i].filePath, paths[i])
+		}
 	})
 
-	t.Run("download file with options", func(t *testing.T) {
+	t.Run("list files with full file info", func(t *testing.T) {
 		t.Parallel()
 
-		const folder = "test-download-file"
+		for info, err := range s3Client.ListFiles(context.Background(), prefix, s3.WithFullFileInfo(), s3.WithMaxKeys(1)) {
+			require.NoError(t, err)
 
-		uploaded := uploadTestFile(t, folder, testFile1Name)
+			require.Equal(t, uploads[0].filePath, info.Path)
+			require.Equal(t, uploads[0].contentType, info.ContentType)
+			require.Equal(t, uploads[0].metaData, info.MetaData)
+			require.NotEmpty(t, info.ChecksumCRC32C)
+		}
+	})
 
-		localPath := localFolder + "/" + uploaded.fileName
+	t.Run("list files with start after and max keys", func(t *testing.T) {
+		t.Parallel()
 
-		err := s3Client.DownloadFile(context.Background(), uploaded.filePath, localPath, s3.WithClientDownloadOptions(s3.ClientGetOptions{}))
-		require.NoError(t, err)
+		paths := make([]string, 0, 2)
 
-		fileBytes, err := os.ReadFile(localPath)
-		require.NoError(t, err)
+		for info, err := range s3Client.ListFiles(context.Background(), prefix, s3.WithStartAfter(uploads[1].filePath), s3.WithMaxKeys(2)) {
+			require.NoError(t, err)
 
-		require.Equal(t, uploaded.content, fileBytes)
+			paths = append(paths, info.Path)
+		}
+
+		require.Equal(t, []string{uploads[2].filePath, uploads[3].filePath}, paths)
 	})
-}
 
-func Test_DownloadDirectory(t *testing.T) {
-	t.Parallel()
+	t.Run("stop iteration", func(t *testing.T) {
+		t.Parallel()
 
-	const (
-		folder      = "test-download-directory"
-		localFolder = "testdata/temp-download-directory"
-	)
+		count := 0
 
-	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+		for _, err := range s3Client.ListFiles(context.Background(), prefix) {
+			require.NoError(t, err)
 
-	t.Cleanup(func() {
-		err := os.RemoveAll(localFolder)
-		require.NoError(t, err)
+			count++
+
+			break
+		}
+
+		require.Equal(t, 1, count)
 	})
 
-	t.Run("download file", func(t *testing.T) {
+	t.Run("list files pages", func(t *testing.T) {
 		t.Parallel()
 
-		uploaded1 := uploadTestFile(t, folder, testFile1Name)
-		uploaded2 := uploadTestFile(t, folder, testFile2Name)
+		paths := make([]string, 0, len(uploads))
+		token := ""
+		pages := 0
 
-		err := s3Client.DownloadDirectory(context.Background(), folder, localFolder, true)
-		require.NoError(t, err)
+		for {
+			page, err := s3Client.ListFilesPage(context.Background(), prefix, s3.WithMaxKeys(2), s3.WithContinuationToken(token))
+			require.NoError(t, err)
 
-		entries, err := os.ReadDir(localFolder)
-		require.NoError(t, err)
+			pages++
 
-		for i := range entries {
-			entry := entries[i]
+			for _, info := range page.Files {
+				paths = append(paths, info.Path)
+			}
 
-			fileName := entry.Name()
+			if page.ContinuationToken == "" {
+				break
+			}
 
-			fileBytes, err := os.ReadFile(localFolder + "/" + entry.Name())
-			require.NoError(t, err)
+			token = page.ContinuationToken
+		}
 
-			if fileName == uploaded1.fileName {
-				require.Equal(t, uploaded1.content, fileBytes)
-			}
+		require.Equal(t, 3, pages)
+		require.Len(t, paths, len(uploads))
 
-			if fileName == uploaded2.fileName {
-				require.Equal(t, uploaded2.content, fileBytes)
-			}
+		for i := range uploads {
+			require.Equal(t, uploads[i].filePath, paths[i])
+		}
+	})
+
+	t.Run("invalid continuation token", func(t *testing.T) {
+		t.Parallel()
+
+		page, err := s3Client.ListFilesPage(context.Background(), prefix, s3.WithMaxKeys(2))
+		require.NoError(t, err)
+		require.NotEmpty(t, page.ContinuationToken)
+
+		_, err = s3Client.ListFilesPage(context.Background(), "other-prefix/", s3.WithContinuationToken(page.ContinuationToken))
+		require.ErrorIs(t, err, s3.ErrInvalidContinuationToken)
+
+		for _, err := range s3Client.ListFiles(context.Background(), prefix, s3.WithContinuationToken("%invalid%")) {
+			require.ErrorIs(t, err, s3.ErrInvalidContinuationToken)
 		}
 	})
 }
 
-func Test_RemoveFile(t *testing.T) {
+func Test_UploadDirectory(t *testing.T) {
 	t.Parallel()
 
-	const folder = "test-remove-file"
+	testUploadDirectory(t, getS3Client, "test-upload-directory")
+}
 
-	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+func testUploadDirectory(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
 
-	t.Run("remove file", func(t *testing.T) {
+	s3Client := newClient(t, s3.WithMD5IntegritySupport(true))
+
+	localPath := t.TempDir()
+
+	files := map[string]string{
+		"a.txt":       "file a",
+		"b.json":      `{"b": true}`,
+		"page":        "<html><body>page</body></html>",
+		"sub/c.txt":   "file c",
+		"skip/d.txt":  "file d",
+		".git/config": "[core]",
+	}
+
+	for relPath, content := range files {
+		localFile := filepath.Join(localPath, filepath.FromSlash(relPath))
+
+		err := os.MkdirAll(filepath.Dir(localFile), 0o700)
+		require.NoError(t, err)
+
+		err = os.WriteFile(localFile, []byte(content), 0o600)
+		require.NoError(t, err)
+	}
+
+	for link, target := range map[string]string{"link.txt": "a.txt", "linkdir": "sub", "sub/loop": ".."} {
+		err := os.Symlink(target, filepath.Join(localPath, link))
+		require.NoError(t, err)
+	}
+
+	uploadedPaths := func(prefix string, uploaded []*s3.UploadedFile) []string {
+		paths := make([]string, 0, len(uploaded))
+
+		for _, file := range uploaded {
+			paths = append(paths, strings.TrimPrefix(file.Path, prefix+"/"))
+		}
+
+		return paths
+	}
+
+	t.Run("upload directory", func(t *testing.T) {
 		t.Parallel()
 
-		uploaded := uploadTestFile(t, folder, testFile1Name)
+		prefix := s3Folder + "-" + uuid.NewString()
 
-		err := s3Client.RemoveFile(context.Background(), uploaded.filePath)
+		uploaded, err := s3Client.UploadDirectory(context.Background(), localPath, prefix)
 		require.NoError(t, err)
+		require.Equal(t, []string{".git/config", "a.txt", "b.json", "page", "skip/d.txt", "sub/c.txt"}, uploadedPaths(prefix, uploaded))
 
-		info, err := minioClient.GetObjectACL(context.Background(), bucketName, uploaded.filePath)
-		require.Error(t, err)
-		require.Nil(t, info)
+		for _, file := range uploaded {
+			expectedChecksum, err := s3.GenerateCheckSumMD5(strings.NewReader(files[strings.TrimPrefix(file.Path, prefix+"/")]))
+			require.NoError(t, err)
+			require.Equal(t, expectedChecksum, file.ChecksumMD5)
+			require.NotEmpty(t, file.ChecksumCRC32C)
+			require.Equal(t, filepath.Join(localPath, filepath.FromSlash(strings.TrimPrefix(file.Path, prefix+"/"))), file.LocalPath)
+		}
+
+		for relPath, expectedContentType := range map[string]string{
+			"b.json": "application/json",
+			"page":   "text/html; charset=utf-8",
+		} {
+			file, err := s3Client.GetFile(context.Background(), prefix+"/"+relPath)
+			require.NoError(t, err)
+
+			content, err := file.Bytes()
+			require.NoError(t, err)
+			require.Equal(t, files[relPath], string(content))
+			require.Equal(t, expectedContentType, file.Info().ContentType)
+		}
 	})
 
-	t.Run("remove file with options", func(t *testing.T) {
+	t.Run("include and exclude patterns", func(t *testing.T) {
 		t.Parallel()
 
-		uploaded := uploadTestFile(t, folder, testFile1Name)
+		prefix := s3Folder + "-" + uuid.NewString()
 
-		err := s3Client.RemoveFile(context.Background(), uploaded.filePath, s3.WithClientRemoveOptions(s3.ClientRemoveOptions{}))
+		uploaded, err := s3Client.UploadDirectory(
+			context.Background(),
+			localPath,
+			prefix,
+			s3.WithInclude("*.txt", "*.json"),
+			s3.WithExclude("skip", ".*"),
+			s3.WithUploadDirectoryConcurrency(1),
+		)
 		require.NoError(t, err)
-
-		info, err := minioClient.GetObjectACL(context.Background(), bucketName, uploaded.filePath)
-		require.Error(t, err)
-		require.Nil(t, info)
+		require.Equal(t, []string{"a.txt", "b.json", "sub/c.txt"}, uploadedPaths(prefix, uploaded))
 	})
-}
 
-func Test_CreateFileLink(t *testing.T) {
-	t.Parallel()
+	t.Run("follow symlinks", func(t *testing.T) {
+		t.Parallel()
 
-	const (
-		expiration = time.Second * 2
-		folder     = "test-create-file-link"
-	)
+		prefix := s3Folder + "-" + uuid.NewString()
 
-	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+		uploaded, err := s3Client.UploadDirectory(
+			context.Background(),
+			localPath,
+			prefix,
+			s3.WithInclude("*.txt"),
+			s3.WithSymlinkPolicy(s3.SymlinkFollow),
+		)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a.txt", "link.txt", "linkdir/c.txt", "skip/d.txt", "sub/c.txt"}, uploadedPaths(prefix, uploaded))
+	})
 
-	uploaded := uploadTestFile(t, folder, testFile1Name)
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
 
-	link, err := s3Client.CreateFileLink(context.Background(), uploaded.filePath, expiration)
-	require.NoError(t, err)
+		_, err := s3Client.UploadDirectory(context.Background(), localPath, s3Folder, s3.WithExclude("["))
+		require.ErrorIs(t, err, pathpkg.ErrBadPattern)
+	})
 
-	require.Equal(t, "/"+bucketName+"/"+uploaded.filePath, link.Path)
+	t.Run("missing local folder", func(t *testing.T) {
+		t.Parallel()
 
-	req, err := http.NewRequest(http.MethodGet, link.String(), nil)
-	require.NoError(t, err)
+		_, err := s3Client.UploadDirectory(context.Background(), filepath.Join(localPath, "missing"), s3Folder)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
 
-	httpClient := &http.Client{}
+func Test_Sync(t *testing.T) {
+	t.Parallel()
 
-	resp, err := httpClient.Do(req)
-	require.NoError(t, err)
+	testSync(t, getS3Client, "test-sync")
 
-	bodyBytes, err := io.ReadAll(resp.Body)
-	require.NoError(t, err)
+	t.Run("sync download skips folder markers", func(t *testing.T) {
+		t.Parallel()
 
-	err = resp.Body.Close()
-	require.NoError(t, err)
+		ctx := context.Background()
+		s3Client := getS3Client(t)
+		localPath := t.TempDir()
+		prefix := "test-sync/" + uuid.NewString()
 
-	require.Equal(t, uploaded.content, bodyBytes)
+		for path, content := range map[string]string{prefix + "/folder/": "", prefix + "/folder/a.txt": "file a"} {
+			size := int64(len(content))
 
-	time.Sleep(expiration + time.Second)
+			_, err := s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader(content), &size, path, contentType, nil))
+			require.NoError(t, err)
+		}
 
-	resp, err = httpClient.Do(req)
-	require.NoError(t, err)
-	require.Equal(t, http.StatusForbidden, resp.StatusCode)
-}
+		plan, err := s3Client.PlanSync(ctx, localPath, prefix, s3.SyncDownload)
+		require.NoError(t, err)
+		require.Len(t, plan.Actions, 1)
+		require.Equal(t, "folder/a.txt", plan.Actions[0].Path)
 
-type uploaded struct {
-	content     []byte
-	lenTestFile int64
-	fileName    string
-	filePath    string
-	contentType string
-	metaData    map[string]string
+		err = s3Client.ApplySync(ctx, plan)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(localPath, "folder", "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "file a", string(content))
+	})
 }
 
-func uploadTestFile(t *testing.T, s3Folder, testFileName string) *uploaded {
+func testSync(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
 	t.Helper()
 
-	content, err := testData.ReadFile(testDataFolder + "/" + testFileName)
-	require.NoError(t, err)
-
-	lenTestFile := int64(len(content))
-
-	fileName := uuid.NewString()
+	writeFiles := func(t *testing.T, localPath string, files map[string]string) {
+		t.Helper()
 
-	filePath := s3Folder + "/" + fileName
+		for relPath, content := range files {
+			localFile := filepath.Join(localPath, filepath.FromSlash(relPath))
 
-	metaData := map[string]string{headerFileName: testFileName}
+			err := os.MkdirAll(filepath.Dir(localFile), 0o700)
+			require.NoError(t, err)
 
-	opt := minio.PutObjectOptions{
-		ContentType:  contentType,
-		UserMetadata: metaData,
+			err = os.WriteFile(localFile, []byte(content), 0o600)
+			require.NoError(t, err)
+		}
 	}
 
-	_, err = minioClient.PutObject(context.Background(), bucketName, filePath, bytes.NewReader(content), lenTestFile, opt)
-	require.NoError(t, err)
+	actionsOf := func(plan *s3.SyncPlan) map[string]s3.SyncReason {
+		actions := make(map[string]s3.SyncReason, len(plan.Actions))
 
-	uploaded := &uploaded{
-		content:     content,
-		lenTestFile: lenTestFile,
-		fileName:    fileName,
-		filePath:    filePath,
-		contentType: contentType,
-		metaData:    metaData,
+		for _, action := range plan.Actions {
+			actions[string(action.Type)+" "+action.Path] = action.Reason
+		}
+
+		return actions
+	}
+
+	t.Run("sync upload", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+		localPath := t.TempDir()
+		prefix := s3Folder + "/" + uuid.NewString()
+
+		writeFiles(t, localPath, map[string]string{"a.txt": "file a", "sub/b.txt": "file b"})
+
+		plan, err := s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncUpload)
+		require.NoError(t, err)
+		require.Equal(t, map[string]s3.SyncReason{
+			"create a.txt":     s3.SyncReasonMissing,
+			"create sub/b.txt": s3.SyncReasonMissing,
+		}, actionsOf(plan))
+
+		err = s3Client.ApplySync(context.Background(), plan)
+		require.NoError(t, err)
+
+		plan, err = s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncUpload)
+		require.NoError(t, err)
+		require.Empty(t, plan.Actions)
+		require.Equal(t, []string{"a.txt", "sub/b.txt"}, plan.Unchanged)
+
+		// same size and modified date, but different content
+		writeFiles(t, localPath, map[string]string{"a.txt": "file A"})
+
+		err = os.Chtimes(filepath.Join(localPath, "a.txt"), time.Time{}, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+
+		extraneous := uploadTestFileWithClient(t, s3Client, prefix, testFile1Name)
+
+		plan, err = s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncUpload, s3.WithSyncDelete())
+		require.NoError(t, err)
+		require.Equal(t, map[string]s3.SyncReason{
+			"update a.txt":                  s3.SyncReasonChecksum,
+			"delete " + extraneous.fileName: s3.SyncReasonExtraneous,
+		}, actionsOf(plan))
+
+		err = s3Client.ApplySync(context.Background(), plan)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(context.Background(), prefix+"/a.txt")
+		require.NoError(t, err)
+
+		content, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "file A", string(content))
+
+		_, err = s3Client.GetFileInfo(context.Background(), extraneous.filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+
+	t.Run("sync download", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+		sourcePath := t.TempDir()
+		localPath := filepath.Join(t.TempDir(), "download")
+		prefix := s3Folder + "/" + uuid.NewString()
+
+		writeFiles(t, sourcePath, map[string]string{"a.txt": "file a", "sub/b.txt": "file b"})
+
+		_, err := s3Client.UploadDirectory(context.Background(), sourcePath, prefix)
+		require.NoError(t, err)
+
+		plan, err := s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncDownload)
+		require.NoError(t, err)
+		require.Len(t, plan.Actions, 2)
+
+		err = s3Client.ApplySync(context.Background(), plan)
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(filepath.Join(localPath, "sub", "b.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "file b", string(content))
+
+		writeFiles(t, localPath, map[string]string{"a.txt": "changed file a", "c.txt": "file c"})
+
+		plan, err = s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncDownload, s3.WithSyncDelete())
+		require.NoError(t, err)
+		require.Equal(t, map[string]s3.SyncReason{
+			"update a.txt": s3.SyncReasonSize,
+			"delete c.txt": s3.SyncReasonExtraneous,
+		}, actionsOf(plan))
+		require.Equal(t, []string{"sub/b.txt"}, plan.Unchanged)
+
+		err = s3Client.ApplySync(context.Background(), plan)
+		require.NoError(t, err)
+
+		content, err = os.ReadFile(filepath.Join(localPath, "a.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "file a", string(content))
+
+		_, err = os.Stat(filepath.Join(localPath, "c.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("sync without checksums compares modified dates", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t, s3.WithCRC32CIntegritySupport(false))
+		localPath := t.TempDir()
+		prefix := s3Folder + "/" + uuid.NewString()
+
+		writeFiles(t, localPath, map[string]string{"a.txt": "file a"})
+
+		plan, err := s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncUpload)
+		require.NoError(t, err)
+
+		err = s3Client.ApplySync(context.Background(), plan)
+		require.NoError(t, err)
+
+		err = os.Chtimes(filepath.Join(localPath, "a.txt"), time.Time{}, time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		plan, err = s3Client.PlanSync(context.Background(), localPath, prefix, s3.SyncUpload)
+		require.NoError(t, err)
+		require.Equal(t, map[string]s3.SyncReason{"update a.txt": s3.SyncReasonModified}, actionsOf(plan))
+	})
+
+	t.Run("missing source folder", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		_, err := s3Client.PlanSync(context.Background(), filepath.Join(t.TempDir(), "missing"), s3Folder, s3.SyncUpload, s3.WithSyncDelete())
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("download outside of the local folder", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+		parentPath := t.TempDir()
+		localPath := filepath.Join(parentPath, "sync")
+
+		plan := &s3.SyncPlan{
+			Direction: s3.SyncDownload,
+			LocalPath: localPath,
+			Prefix:    s3Folder + "/" + uuid.NewString(),
+			Actions: []*s3.SyncAction{
+				{Path: "../escaped.txt", Type: s3.SyncCreate, Reason: s3.SyncReasonMissing},
+				{Path: "folder/", Type: s3.SyncCreate, Reason: s3.SyncReasonMissing},
+			},
+		}
+
+		err := s3Client.ApplySync(context.Background(), plan)
+		require.ErrorIs(t, err, s3.ErrInvalidPath)
+
+		var bulkErr *s3.BulkError
+
+		require.ErrorAs(t, err, &bulkErr)
+		require.ElementsMatch(t, []string{"../escaped.txt", "folder/"}, bulkErr.FailedKeys())
+
+		_, err = os.Stat(filepath.Join(parentPath, "escaped.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_DownloadFile(t *testing.T) {
+	t.Parallel()
+
+	const (
+		folder      = "test-download-file"
+		localFolder = "testdata/temp-download-file"
+	)
+
+	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+
+	t.Cleanup(func() {
+		err := os.RemoveAll(localFolder)
+		require.NoError(t, err)
+	})
+
+	t.Run("download file", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFile(t, folder, testFile1Name)
+
+		localPath := localFolder + "/" + uploaded.fileName
+
+		err := s3Client.DownloadFile(context.Background(), uploaded.filePath, localPath)
+		require.NoError(t, err)
+
+		fileBytes, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.content, fileBytes)
+	})
+
+	t.Run("download file with options", func(t *testing.T) {
+		t.Parallel()
+
+		const folder = "test-download-file"
+
+		uploaded := uploadTestFile(t, folder, testFile1Name)
+
+		localPath := localFolder + "/" + uploaded.fileName
+
+		err := s3Client.DownloadFile(context.Background(), uploaded.filePath, localPath, s3.WithClientDownloadOptions(s3.ClientGetOptions{}))
+		require.NoError(t, err)
+
+		fileBytes, err := os.ReadFile(localPath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.content, fileBytes)
+	})
+}
+
+func Test_DownloadDirectory(t *testing.T) {
+	t.Parallel()
+
+	const (
+		folder      = "test-download-directory"
+		localFolder = "testdata/temp-download-directory"
+	)
+
+	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+
+	t.Cleanup(func() {
+		err := os.RemoveAll(localFolder)
+		require.NoError(t, err)
+	})
+
+	t.Run("download file", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded1 := uploadTestFile(t, folder, testFile1Name)
+		uploaded2 := uploadTestFile(t, folder, testFile2Name)
+
+		err := s3Client.DownloadDirectory(context.Background(), folder, localFolder, true)
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(localFolder)
+		require.NoError(t, err)
+
+		for i := range entries {
+			entry := entries[i]
+
+			fileName := entry.Name()
+
+			fileBytes, err := os.ReadFile(localFolder + "/" + entry.Name())
+			require.NoError(t, err)
+
+			if fileName == uploaded1.fileName {
+				require.Equal(t, uploaded1.content, fileBytes)
+			}
+
+			if fileName == uploaded2.fileName {
+				require.Equal(t, uploaded2.content, fileBytes)
+			}
+		}
+	})
+}
+
+func Test_RemoveFile(t *testing.T) {
+	t.Parallel()
+
+	const folder = "test-remove-file"
+
+	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+
+	t.Run("remove file", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFile(t, folder, testFile1Name)
+
+		err := s3Client.RemoveFile(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		info, err := minioClient.GetObjectACL(context.Background(), bucketName, uploaded.filePath)
+		require.Error(t, err)
+		require.Nil(t, info)
+	})
+
+	t.Run("remove file with options", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFile(t, folder, testFile1Name)
+
+		err := s3Client.RemoveFile(context.Background(), uploaded.filePath, s3.WithClientRemoveOptions(s3.ClientRemoveOptions{}))
+		require.NoError(t, err)
+
+		info, err := minioClient.GetObjectACL(context.Background(), bucketName, uploaded.filePath)
+		require.Error(t, err)
+		require.Nil(t, info)
+	})
+}
+
+func Test_RemoveDirectory(t *testing.T) {
+	t.Parallel()
+
+	testRemoveDirectory(t, getS3Client, "test-remove-directory")
+
+	t.Run("remove all versions", func(t *testing.T) {
+		t.Parallel()
+
+		const versionedBucketName = "test-remove-versions"
+
+		err := minioClient.MakeBucket(context.Background(), versionedBucketName, minio.MakeBucketOptions{})
+		require.NoError(t, err)
+
+		err = minioClient.EnableVersioning(context.Background(), versionedBucketName)
+		require.NoError(t, err)
+
+		clientDetails := &s3.ClientDetails{
+			Host:         s3URL,
+			AccessKey:    s3User,
+			AccessSecret: s3Pwd,
+			BucketName:   versionedBucketName,
+		}
+
+		s3Client, err := s3.NewClient(clientDetails, s3.WithCRC32CIntegritySupport(false))
+		require.NoError(t, err)
+
+		t.Cleanup(s3Client.Close)
+
+		const folder = "test-remove-versions"
+
+		filePath := folder + "/" + uuid.NewString()
+
+		for range 2 {
+			_, err = s3Client.UploadFile(context.Background(), s3.NewUpload(strings.NewReader("version"), nil, filePath, contentType, nil))
+			require.NoError(t, err)
+		}
+
+		removed, err := s3Client.RemoveDirectory(context.Background(), folder, s3.WithRemoveAllVersions())
+		require.NoError(t, err)
+		require.Equal(t, []string{filePath}, removed)
+
+		for objInfo := range minioClient.ListObjects(context.Background(), versionedBucketName, minio.ListObjectsOptions{
+			Prefix:       folder + "/",
+			Recursive:    true,
+			WithVersions: true,
+		}) {
+			require.NoError(t, objInfo.Err)
+			require.Fail(t, "version left", objInfo.Key)
+		}
+	})
+}
+
+func testRemoveDirectory(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
+
+	s3Client := newClient(t)
+
+	uploadFolder := func(t *testing.T, folder string) []string {
+		t.Helper()
+
+		paths := make([]string, 0, 3)
+
+		for _, subFolder := range []string{folder, folder, folder + "/sub"} {
+			paths = append(paths, uploadTestFileWithClient(t, s3Client, subFolder, testFile1Name).filePath)
+		}
+
+		slices.Sort(paths)
+
+		return paths
+	}
+
+	t.Run("remove directory", func(t *testing.T) {
+		t.Parallel()
+
+		folder := s3Folder + "/" + uuid.NewString()
+
+		paths := uploadFolder(t, folder)
+		sibling := uploadTestFileWithClient(t, s3Client, folder+"-sibling", testFile1Name)
+
+		removed, err := s3Client.RemoveDirectory(context.Background(), folder)
+		require.NoError(t, err)
+		require.Equal(t, paths, removed)
+
+		for _, path := range paths {
+			_, err = s3Client.GetFileInfo(context.Background(), path)
+			require.ErrorIs(t, err, s3.ErrNotFound)
+		}
+
+		// folders sharing the prefix are kept
+		_, err = s3Client.GetFileInfo(context.Background(), sibling.filePath)
+		require.NoError(t, err)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		t.Parallel()
+
+		folder := s3Folder + "/" + uuid.NewString()
+
+		paths := uploadFolder(t, folder)
+
+		removed, err := s3Client.RemoveDirectory(context.Background(), folder+"/", s3.WithRemoveDirectoryDryRun())
+		require.NoError(t, err)
+		require.Equal(t, paths, removed)
+
+		for _, path := range paths {
+			_, err = s3Client.GetFileInfo(context.Background(), path)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("remove empty directory", func(t *testing.T) {
+		t.Parallel()
+
+		removed, err := s3Client.RemoveDirectory(context.Background(), s3Folder+"/"+uuid.NewString(), s3.WithRemoveAllVersions())
+		require.NoError(t, err)
+		require.Empty(t, removed)
+	})
+}
+
+func Test_CopyFile(t *testing.T) {
+	t.Parallel()
+
+	testCopyFile(t, getS3Client, "test-copy-file")
+
+	t.Run("copy file into another bucket", func(t *testing.T) {
+		t.Parallel()
+
+		const otherBucketName = "test-copy-bucket"
+
+		err := minioClient.MakeBucket(context.Background(), otherBucketName, minio.MakeBucketOptions{})
+		require.NoError(t, err)
+
+		s3Client := getS3Client(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-copy-file", testFile1Name)
+
+		_, err = s3Client.MoveFile(context.Background(), uploaded.filePath, uploaded.filePath, s3.WithDestinationBucket(otherBucketName))
+		require.NoError(t, err)
+
+		objInfo, err := minioClient.StatObject(context.Background(), otherBucketName, uploaded.filePath, minio.StatObjectOptions{})
+		require.NoError(t, err)
+		require.Equal(t, uploaded.lenTestFile, objInfo.Size)
+		require.Equal(t, uploaded.contentType, objInfo.ContentType)
+
+		_, err = s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+}
+
+func testCopyFile(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client, s3Folder string) {
+	t.Helper()
+
+	s3Client := newClient(t, s3.WithMD5IntegritySupport(true))
+
+	t.Run("copy file", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name)
+		dstPath := s3Folder + "/" + uuid.NewString()
+
+		info, err := s3Client.CopyFile(context.Background(), uploaded.filePath, dstPath)
+		require.NoError(t, err)
+		require.Equal(t, uploaded.lenTestFile, info.Size)
+
+		expectedChecksum, err := s3.GenerateCheckSumMD5(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+		require.Equal(t, expectedChecksum, info.ChecksumMD5)
+
+		file, err := s3Client.GetFile(context.Background(), dstPath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		content, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, uploaded.content, content)
+
+		fileInfo := file.Info()
+		require.Equal(t, uploaded.contentType, fileInfo.ContentType)
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumMD5)
+		require.Equal(t, info.ChecksumCRC32C, fileInfo.ChecksumCRC32C)
+
+		_, err = s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+	})
+
+	t.Run("copy file with replaced metadata and content type", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name)
+		dstPath := s3Folder + "/" + uuid.NewString()
+
+		metaData := map[string]string{"Copied": "true"}
+
+		info, err := s3Client.CopyFile(
+			context.Background(),
+			uploaded.filePath,
+			dstPath,
+			s3.WithCopyMetaData(metaData),
+			s3.WithCopyContentType("application/json"),
+		)
+		require.NoError(t, err)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), dstPath)
+		require.NoError(t, err)
+		require.Equal(t, "application/json", fileInfo.ContentType)
+		require.Equal(t, metaData, fileInfo.MetaData)
+		require.Equal(t, info.Integrity, fileInfo.Integrity)
+	})
+
+	t.Run("copy file with native checksums", func(t *testing.T) {
+		t.Parallel()
+
+		nativeClient := newClient(t, s3.WithNativeChecksums(true))
+
+		uploaded := uploadTestFileWithClient(t, nativeClient, s3Folder, testFile1Name)
+		dstPath := s3Folder + "/" + uuid.NewString()
+
+		_, err := nativeClient.CopyFile(context.Background(), uploaded.filePath, dstPath)
+		require.NoError(t, err)
+
+		expectedChecksum, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		fileInfo, err := nativeClient.GetFileInfo(context.Background(), dstPath)
+		require.NoError(t, err)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumCRC32C)
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+	})
+
+	t.Run("move file", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name)
+		dstPath := s3Folder + "/" + uuid.NewString()
+
+		info, err := s3Client.MoveFile(context.Background(), uploaded.filePath, dstPath)
+		require.NoError(t, err)
+		require.Equal(t, uploaded.lenTestFile, info.Size)
+
+		_, err = s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), dstPath)
+		require.NoError(t, err)
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, info.Integrity, fileInfo.Integrity)
+	})
+
+	t.Run("move file onto itself", func(t *testing.T) {
+		t.Parallel()
+
+		uploaded := uploadTestFileWithClient(t, s3Client, s3Folder, testFile1Name)
+
+		_, err := s3Client.MoveFile(context.Background(), uploaded.filePath, uploaded.filePath, s3.WithCopyContentType("text/csv"))
+		require.NoError(t, err)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+		require.Equal(t, "text/csv", fileInfo.ContentType)
+	})
+
+	t.Run("copy missing file", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3Client.CopyFile(context.Background(), s3Folder+"/"+uuid.NewString(), s3Folder+"/"+uuid.NewString())
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+}
+
+func Test_CreateFileLink(t *testing.T) {
+	t.Parallel()
+
+	const (
+		expiration = time.Second * 2
+		folder     = "test-create-file-link"
+	)
+
+	s3Client := getS3Client(t, s3.WithCRC32CIntegritySupport(false))
+
+	uploaded := uploadTestFile(t, folder, testFile1Name)
+
+	link, err := s3Client.CreateFileLink(context.Background(), uploaded.filePath, expiration)
+	require.NoError(t, err)
+
+	require.Equal(t, "/"+bucketName+"/"+uploaded.filePath, link.Path)
+
+	req, err := http.NewRequest(http.MethodGet, link.String(), nil)
+	require.NoError(t, err)
+
+	httpClient := &http.Client{}
+
+	resp, err := httpClient.Do(req)
+	require.NoError(t, err)
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	err = resp.Body.Close()
+	require.NoError(t, err)
+
+	require.Equal(t, uploaded.content, bodyBytes)
+
+	time.Sleep(expiration + time.Second)
+
+	resp, err = httpClient.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func Test_CreateFileLinkOptions(t *testing.T) {
+	t.Parallel()
+
+	s3Client := getS3Client(t)
+
+	uploaded := uploadTestFile(t, "test-create-file-link-options", testFile1Name)
+
+	t.Run("attachment", func(t *testing.T) {
+		t.Parallel()
+
+		link, err := s3Client.CreateFileLink(context.Background(), uploaded.filePath, time.Minute,
+			s3.WithAttachment("Übersicht 2024.txt"),
+			s3.WithResponseContentType("application/octet-stream"),
+			s3.WithResponseCacheControl("private, max-age=60"),
+		)
+		require.NoError(t, err)
+
+		resp, err := http.Get(link.String()) //nolint:noctx // test request
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, `attachment; filename="_bersicht 2024.txt"; filename*=UTF-8''%C3%9Cbersicht%202024.txt`,
+			resp.Header.Get("Content-Disposition"))
+		require.Equal(t, "application/octet-stream", resp.Header.Get("Content-Type"))
+		require.Equal(t, "private, max-age=60", resp.Header.Get("Cache-Control"))
+	})
+
+	t.Run("public endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		const publicHost = "files.example.com"
+
+		publicClient := getS3Client(t, s3.WithPublicEndpoint(publicHost, true))
+
+		link, err := publicClient.CreateFileLink(context.Background(), uploaded.filePath, time.Minute)
+		require.NoError(t, err)
+		require.Equal(t, "https", link.Scheme)
+		require.Equal(t, publicHost, link.Host)
+
+		// the load balancer forwards the request with the public host to the internal endpoint
+		link.Scheme = "http"
+		link.Host = s3URL
+
+		req, err := http.NewRequest(http.MethodGet, link.String(), nil)
+		require.NoError(t, err)
+
+		req.Host = publicHost
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("empty public endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewClient(&s3.ClientDetails{
+			Host:         s3URL,
+			AccessKey:    s3User,
+			AccessSecret: s3Pwd,
+			BucketName:   bucketName,
+		}, s3.WithPublicEndpoint("", true))
+		require.ErrorIs(t, err, s3.ErrEmptyHost)
+	})
+}
+
+func Test_CreateUploadLink(t *testing.T) {
+	t.Parallel()
+
+	s3Client := getS3Client(t, s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true))
+
+	testUploadLinkOptions(t, s3Client)
+
+	t.Run("upload using link", func(t *testing.T) {
+		t.Parallel()
+
+		content := []byte("uploaded using a presigned link")
+		path := "test-create-upload-link/" + uuid.NewString()
+
+		checksum, err := s3.GenerateCheckSum(s3.ChecksumAlgorithmSHA256, bytes.NewReader(content))
+		require.NoError(t, err)
+
+		link, err := s3Client.CreateUploadLink(context.Background(), path, time.Minute,
+			s3.WithUploadLinkContentType(contentType),
+			s3.WithUploadLinkMetaData(map[string]string{headerFileName: testFile1Name}),
+			s3.WithUploadLinkChecksum(s3.ChecksumAlgorithmSHA256, checksum),
+		)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPut, link.URL.String(), bytes.NewReader(content))
+		require.NoError(t, err)
+
+		req.Header = link.Headers
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, contentType, fileInfo.ContentType)
+		require.Equal(t, testFile1Name, fileInfo.MetaData[headerFileName])
+		require.Equal(t, checksum, fileInfo.Integrity.Checksum(s3.ChecksumAlgorithmSHA256))
+
+		file, err := s3Client.GetFile(context.Background(), path, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		fileBytes, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+		require.Equal(t, content, fileBytes)
+	})
+
+	t.Run("signed headers are enforced", func(t *testing.T) {
+		t.Parallel()
+
+		link, err := s3Client.CreateUploadLink(context.Background(), "test-create-upload-link/"+uuid.NewString(), time.Minute,
+			s3.WithUploadLinkContentType(contentType),
+		)
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodPut, link.URL.String(), strings.NewReader("content"))
+		require.NoError(t, err)
+
+		req.Header.Set("Content-Type", "application/octet-stream")
+
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func Test_CreateUploadPolicy(t *testing.T) {
+	t.Parallel()
+
+	s3Client := getS3Client(t, s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true))
+
+	prefix := "test-create-upload-policy/" + uuid.NewString() + "/"
+
+	policy, err := s3Client.CreateUploadPolicy(context.Background(), prefix, time.Minute,
+		s3.WithPathPrefix(),
+		s3.WithUploadLinkContentType(contentType),
+		s3.WithContentLengthRange(1, 1<<10),
+		s3.WithRequiredChecksums(s3.ChecksumAlgorithmSHA256),
+	)
+	require.NoError(t, err)
+
+	t.Run("upload using form", func(t *testing.T) {
+		t.Parallel()
+
+		content := []byte("uploaded using a presigned policy")
+		path := prefix + "file.txt"
+
+		checksum, err := s3.GenerateCheckSum(s3.ChecksumAlgorithmSHA256, bytes.NewReader(content))
+		require.NoError(t, err)
+
+		formData := maps.Clone(policy.FormData)
+		formData["key"] = path
+		formData["x-amz-meta-checksum-sha256"] = checksum
+
+		resp := postUploadForm(t, policy.URL.String(), formData, content)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), path)
+		require.NoError(t, err)
+		require.Equal(t, contentType, fileInfo.ContentType)
+		require.Equal(t, checksum, fileInfo.Integrity.Checksum(s3.ChecksumAlgorithmSHA256))
+	})
+
+	t.Run("conditions are enforced", func(t *testing.T) {
+		t.Parallel()
+
+		formData := maps.Clone(policy.FormData)
+		formData["key"] = "test-create-upload-policy/" + uuid.NewString()
+
+		resp := postUploadForm(t, policy.URL.String(), formData, []byte("outside of the prefix"))
+		require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+		formData["key"] = prefix + "too-large.txt"
+
+		resp = postUploadForm(t, policy.URL.String(), formData, bytes.Repeat([]byte("a"), 2<<10))
+		require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func postUploadForm(t *testing.T, url string, formData map[string]string, content []byte) *http.Response {
+	t.Helper()
+
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	for key, value := range formData {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+
+	part, err := writer.CreateFormFile("file", "file.txt")
+	require.NoError(t, err)
+
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	resp, err := http.Post(url, writer.FormDataContentType(), body) //nolint:noctx // test request
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	return resp
+}
+
+func testUploadLinkOptions(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	t.Run("upload link with policy only options", func(t *testing.T) {
+		t.Parallel()
+
+		for _, option := range []s3.UploadLinkOption{
+			s3.WithPathPrefix(),
+			s3.WithContentLengthRange(0, 1<<10),
+			s3.WithRequiredMetaData(headerFileName),
+			s3.WithRequiredChecksums(s3.ChecksumAlgorithmSHA256),
+		} {
+			_, err := s3Client.CreateUploadLink(context.Background(), "test-upload-link-options/file", time.Minute, option)
+			require.ErrorIs(t, err, s3.ErrNotSupported)
+		}
+	})
+
+	t.Run("invalid checksum algorithm", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3Client.CreateUploadLink(context.Background(), "test-upload-link-options/file", time.Minute,
+			s3.WithUploadLinkChecksum("unknown", "checksum"),
+		)
+		require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+
+		_, err = s3Client.CreateUploadPolicy(context.Background(), "test-upload-link-options/file", time.Minute,
+			s3.WithRequiredChecksums("unknown"),
+		)
+		require.ErrorIs(t, err, s3.ErrInvalidChecksumAlgorithm)
+	})
+}
+
+type uploaded struct {
+	content     []byte
+	lenTestFile int64
+	fileName    string
+	filePath    string
+	contentType string
+	metaData    map[string]string
+}
+
+func uploadTestFile(t *testing.T, s3Folder, testFileName string) *uploaded {
+	t.Helper()
+
+	content, err := testData.ReadFile(testDataFolder + "/" + testFileName)
+	require.NoError(t, err)
+
+	lenTestFile := int64(len(content))
+
+	fileName := uuid.NewString()
+
+	filePath := s3Folder + "/" + fileName
+
+	metaData := map[string]string{headerFileName: testFileName}
+
+	opt := minio.PutObjectOptions{
+		ContentType:  contentType,
+		UserMetadata: metaData,
+	}
+
+	_, err = minioClient.PutObject(context.Background(), bucketName, filePath, bytes.NewReader(content), lenTestFile, opt)
+	require.NoError(t, err)
+
+	uploaded := &uploaded{
+		content:     content,
+		lenTestFile: lenTestFile,
+		fileName:    fileName,
+		filePath:    filePath,
+		contentType: contentType,
+		metaData:    metaData,
 	}
 
 	return uploaded
@@ -637,7 +1729,9 @@ type testEnvironment struct {
 }
 
 func setupTestEnvironment(ctx context.Context, bucketName string) (*testEnvironment, error) {
-	container, err := testutils.NewContainer(ctx, testutils.DefaultImage)
+	container, err := testutils.NewContainer(ctx, testutils.DefaultImage,
+		testcontainers.WithEnv(map[string]string{"MINIO_KMS_SECRET_KEY": testKMSSecret}),
+	)
 	if err != nil {
 		return nil, err
 	}
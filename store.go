@@ -0,0 +1,1241 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"maps"
+	"net/textproto"
+	"net/url"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+)
+
+const defaultContentType = "application/octet-stream"
+
+// objectStore is the storage of a client which does not talk to a s3 server.
+type objectStore interface {
+	// putObject stores the content under the given path and returns the number of bytes written.
+	// attrs is called once the content has been consumed, so that checksums computed while streaming can be stored.
+	putObject(path string, content io.Reader, attrs func() *objectAttributes) (int64, error)
+	// getObject opens the content of the object under the given path.
+	getObject(path string) (io.ReadSeekCloser, *objectAttributes, error)
+	// statObject returns the attributes of the object under the given path.
+	statObject(path string) (*objectAttributes, error)
+	// listObjects returns the sorted paths of all objects under the given prefix.
+	// Unless recursive is set, objects in sub folders are omitted.
+	listObjects(prefix string, recursive bool) ([]string, error)
+	// removeObject removes the object under the given path. Removing a missing object is not an error.
+	removeObject(path string) error
+	// getLifecycle returns a copy of the lifecycle configuration of the store.
+	getLifecycle() (*lifecycle.Configuration, error)
+	// setLifecycle replaces the lifecycle configuration of the store.
+	setLifecycle(config *lifecycle.Configuration) error
+	// setTags replaces the tags of the object under the given path.
+	setTags(path string, tags map[string]string) error
+	// objectLink returns a link to the object under the given path, the options apply to download links only.
+	objectLink(path string, expiration time.Duration, options []FileLinkOption) (*url.URL, error)
+}
+
+type objectAttributes struct {
+	size         int64
+	contentType  string
+	metaData     map[string]string
+	tags         map[string]string
+	modifiedDate time.Time
+}
+
+// storeClient implements the Client interface on top of an objectStore.
+type storeClient struct {
+	store        objectStore
+	keyProvider  KeyProvider
+	concurrency  int
+	lifecycleMtx sync.Mutex
+	integritySettings
+}
+
+func newStoreClient(store objectStore, options ...ClientOption) (*storeClient, error) {
+	settings := &client{
+		concurrency:       defaultConcurrency,
+		integritySettings: defaultIntegritySettings(),
+	}
+
+	for i := range options {
+		if err := options[i](settings); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkEncryption(settings.encryption); err != nil {
+		return nil, err
+	}
+
+	client := &storeClient{
+		store:             store,
+		keyProvider:       settings.keyProvider,
+		concurrency:       settings.concurrency,
+		integritySettings: settings.integritySettings,
+	}
+
+	// stores have no standard checksum headers and keep all checksums in the metadata
+	client.useNativeChecksums = false
+
+	return client, nil
+}
+
+func (c *storeClient) UploadFile(ctx context.Context, upload *Upload, options ...UploadOption) (*UploadInfo, error) {
+	const errMessage = "failed to upload file: %w"
+
+	content, err := uploadReader(upload)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	stream := NewStreamUpload(content, upload.Path, upload.ContentType, upload.MetaData)
+	stream.Tags = upload.Tags
+
+	info, err := c.putObject(ctx, stream, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) UploadStream(ctx context.Context, upload *StreamUpload, options ...UploadOption) (*UploadInfo, error) {
+	const errMessage = "failed to upload stream: %w"
+
+	info, err := c.putObject(ctx, upload, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) putObject(ctx context.Context, upload *StreamUpload, options ...UploadOption) (*UploadInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	opts := new(uploadOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if opts.retention != nil || opts.legalHold {
+		return nil, fmt.Errorf("%w: object lock", ErrNotSupported)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, err
+	}
+
+	if _, err := objectTags(upload.Tags); err != nil {
+		return nil, err
+	}
+
+	metaData := make(map[string]string)
+
+	maps.Copy(metaData, opts.clientOptions.UserMetadata)
+	maps.Copy(metaData, upload.MetaData)
+
+	contentType := upload.ContentType
+
+	if contentType == "" {
+		contentType = opts.clientOptions.ContentType
+	}
+
+	if contentType == "" {
+		contentType = defaultContentType
+	}
+
+	var integrity Integrity
+
+	hasher, err := c.newIntegrityHasher()
+	if err != nil {
+		return nil, err
+	}
+
+	content := io.TeeReader(upload, hasher)
+
+	var compressor *compressingReader
+
+	if opts.compression != CompressionNone {
+		compressor, err = newCompressingReader(content, opts.compression)
+		if err != nil {
+			return nil, err
+		}
+
+		content = compressor
+		metaData[keyCompression] = string(opts.compression)
+	}
+
+	attrs := func() *objectAttributes {
+		integrity = hasher.integrity()
+
+		maps.Copy(metaData, c.integrityMetaData(integrity, Integrity{}))
+
+		if compressor != nil {
+			metaData[keyCompressionSize] = strconv.FormatInt(compressor.size, 10)
+		}
+
+		return &objectAttributes{
+			contentType:  contentType,
+			metaData:     canonicalMetaData(metaData),
+			tags:         maps.Clone(upload.Tags),
+			modifiedDate: time.Now().UTC(),
+		}
+	}
+
+	if c.keyProvider != nil {
+		var envelope map[string]string
+
+		content, envelope, err = sealEnvelope(ctx, c.keyProvider, upload.Path, content)
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(metaData, envelope)
+	}
+
+	size, err := c.store.putObject(upload.Path, content, attrs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case compressor != nil:
+		size = compressor.size
+	case c.keyProvider != nil:
+		size = plaintextSize(size, envelopeChunkSize)
+	}
+
+	info := &UploadInfo{
+		Size:      size,
+		Integrity: integrity,
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) GetFile(ctx context.Context, path string, options ...GetOption) (File, error) {
+	const errMessage = "failed to get file from s3: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	opts := new(getOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := checkVersionID(opts.versionID); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	object, attrs, err := c.store.getObject(path)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	info := attrs.fileInfo(path)
+
+	content, _, err := openFile(ctx, c.keyProvider, object, info, Integrity{})
+	if err != nil {
+		object.Close()
+
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	content, err = c.handleIntegrity(content, info, Integrity{}, opts)
+	if err != nil {
+		object.Close()
+
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return &file{ReadCloser: content, info: info}, nil
+}
+
+func (c *storeClient) GetFileInfo(ctx context.Context, path string, options ...FileInfoOption) (*FileInfo, error) {
+	const errMessage = "failed to get file info: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	opts := new(fileInfoOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := checkVersionID(opts.versionID); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	attrs, err := c.store.statObject(path)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	info := attrs.fileInfo(path)
+
+	if _, err := takeStoredContent(info); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	c.handleGetFileInfoIntegrity(info, Integrity{})
+
+	return info, nil
+}
+
+func (c *storeClient) GetDirectory(ctx context.Context, path string, options ...GetDirectoryOption) ([]File, error) {
+	const errMessage = "failed to get directory: %w"
+
+	files, err := getDirectory(ctx, c.listKeys, c.concurrency, path, c.GetFile, options...)
+	if err != nil {
+		return files, fmt.Errorf(errMessage, err)
+	}
+
+	return files, nil
+}
+
+func (c *storeClient) GetDirectoryInfos(ctx context.Context, path string, options ...GetDirectoryOption) ([]*FileInfo, error) {
+	const errMessage = "failed to get directory: %w"
+
+	fileInfos, err := getDirectoryInfos(ctx, c.listKeys, c.concurrency, path, c.GetFileInfo, options...)
+	if err != nil {
+		return fileInfos, fmt.Errorf(errMessage, err)
+	}
+
+	return fileInfos, nil
+}
+
+// listKeys returns an iterator over the keys of all objects under the given prefix.
+func (c *storeClient) listKeys(_ context.Context, prefix string, recursive bool) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		keys, err := c.store.listObjects(prefix, recursive)
+		if err != nil {
+			yield("", err)
+
+			return
+		}
+
+		for _, key := range keys {
+			if !yield(key, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (c *storeClient) DownloadFile(ctx context.Context, path, localPath string, options ...DownloadOption) error {
+	const errMessage = "failed to download file: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	opts := new(downloadOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := checkVersionID(opts.versionID); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	object, attrs, err := c.store.getObject(path)
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	defer object.Close()
+
+	content, _, err := openFile(ctx, c.keyProvider, object, attrs.fileInfo(path), Integrity{})
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := writeLocalFile(localPath, content); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) ListFiles(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileInfo, error] {
+	return listFiles(ctx, prefix, options, c.listFileInfos, c.describeListedFile)
+}
+
+// listFileInfos returns an iterator over the files under the given prefix. The files only contain their path,
+// the details are read by describeListedFile.
+func (c *storeClient) listFileInfos(_ context.Context, prefix, startAfter string, _ int) iter.Seq2[*FileInfo, error] {
+	return func(yield func(*FileInfo, error) bool) {
+		keys, err := c.store.listObjects(prefix, true)
+		if err != nil {
+			yield(nil, err)
+
+			return
+		}
+
+		for _, key := range keys {
+			if key <= startAfter {
+				continue
+			}
+
+			if !yield(&FileInfo{Name: pathpkg.Base(key), Path: key}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// describeListedFile returns the file info of the listed file with the details requested by the options.
+func (c *storeClient) describeListedFile(_ context.Context, listed *FileInfo, opts *listOptions) (*FileInfo, error) {
+	attrs, err := c.store.statObject(listed.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	info := attrs.fileInfo(listed.Path)
+
+	if opts.fullFileInfo {
+		if _, err := takeStoredContent(info); err != nil {
+			return nil, err
+		}
+
+		c.handleGetFileInfoIntegrity(info, Integrity{})
+	} else {
+		info = &FileInfo{
+			Name:         info.Name,
+			Path:         info.Path,
+			Size:         info.Size,
+			ModifiedDate: info.ModifiedDate,
+		}
+	}
+
+	if opts.tags {
+		info.Tags = attrs.tagMap()
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) ListFilesPage(ctx context.Context, prefix string, options ...ListOption) (*FilePage, error) {
+	return listFilesPage(ctx, c.ListFiles, prefix, options...)
+}
+
+func (c *storeClient) UploadDirectory(
+	ctx context.Context,
+	localPath, prefix string,
+	options ...UploadDirectoryOption,
+) ([]*UploadedFile, error) {
+	const errMessage = "failed to upload directory: %w"
+
+	uploaded, err := uploadDirectory(ctx, localPath, prefix, c.concurrency, c.UploadFile, options...)
+	if err != nil {
+		return uploaded, fmt.Errorf(errMessage, err)
+	}
+
+	return uploaded, nil
+}
+
+func (c *storeClient) PlanSync(
+	ctx context.Context,
+	localPath, prefix string,
+	direction SyncDirection,
+	options ...SyncOption,
+) (*SyncPlan, error) {
+	const errMessage = "failed to plan sync: %w"
+
+	plan, err := planSync(ctx, c, c.algorithms, localPath, prefix, direction, options...)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return plan, nil
+}
+
+func (c *storeClient) ApplySync(ctx context.Context, plan *SyncPlan) error {
+	const errMessage = "failed to apply sync: %w"
+
+	if err := applySync(ctx, c, c.concurrency, plan); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) DownloadDirectory(ctx context.Context, path, localPath string, recursive bool, options ...DownloadOption) error {
+	const errMessage = "failed to download files from s3: %w"
+
+	if err := downloadDirectory(ctx, c.listKeys, c.concurrency, path, localPath, recursive, c.DownloadFile, options...); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) RemoveFile(ctx context.Context, path string, options ...RemoveOption) error {
+	const errMessage = "failed to remove file: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	opts := new(removeOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := checkVersionID(opts.versionID); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := c.store.removeObject(path); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) RemoveDirectory(ctx context.Context, path string, options ...RemoveDirectoryOption) ([]string, error) {
+	const errMessage = "failed to remove directory: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	opts := new(removeDirectoryOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	// stores keep a single version of every file
+	keys, err := c.store.listObjects(folderPrefix(path), true)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if opts.dryRun {
+		return keys, nil
+	}
+
+	results := make([]bulkResult[string], 0, len(keys))
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+
+		results = append(results, bulkResult[string]{key: key, value: key, err: c.store.removeObject(key)})
+	}
+
+	removedKeys, err := collectResults(results, ctx.Err(), OperationRemoveFile, true)
+	if err != nil {
+		return removedKeys, fmt.Errorf(errMessage, err)
+	}
+
+	return removedKeys, nil
+}
+
+func (c *storeClient) CopyFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error) {
+	const errMessage = "failed to copy file: %w"
+
+	opts := new(copyOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	info, err := c.copyFile(ctx, srcPath, dstPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) MoveFile(ctx context.Context, srcPath, dstPath string, options ...CopyOption) (*UploadInfo, error) {
+	const errMessage = "failed to move file: %w"
+
+	opts := new(copyOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	info, err := c.copyFile(ctx, srcPath, dstPath, opts)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if srcPath == dstPath {
+		return info, nil
+	}
+
+	if err := c.store.removeObject(srcPath); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return info, nil
+}
+
+// copyFile copies the file within the store, keeping the metadata, content type and checksums of the source
+// unless they are replaced by the options. Stores hold a single bucket, so copying into another bucket is not supported.
+// Stores keep a single version of every file, so copying a specific version is not supported either.
+func (c *storeClient) copyFile(ctx context.Context, srcPath, dstPath string, opts *copyOptions) (*UploadInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.bucketName != "" {
+		return nil, ErrNotSupported
+	}
+
+	if err := checkVersionID(opts.srcVersionID); err != nil {
+		return nil, err
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, err
+	}
+
+	if err := checkEncryption(opts.srcEncryption); err != nil {
+		return nil, err
+	}
+
+	object, attrs, err := c.store.getObject(srcPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer object.Close()
+
+	source := attrs.fileInfo(srcPath)
+
+	stored, err := takeStoredContent(source)
+	if err != nil {
+		return nil, err
+	}
+
+	checksums := storedChecksums(source, Integrity{})
+
+	if opts.rotateKey {
+		rotated, err := rewrapKey(ctx, c.keyProvider, stored.envelope)
+		if err != nil {
+			return nil, err
+		}
+
+		if rotated == nil {
+			return &UploadInfo{Size: source.Size, Integrity: c.enabledIntegrity(checksums)}, nil
+		}
+
+		stored.envelope = rotated
+	}
+
+	metaData := source.MetaData
+
+	if opts.replaceMetaData {
+		metaData = maps.Clone(opts.metaData)
+	}
+
+	if metaData == nil {
+		metaData = make(map[string]string)
+	}
+
+	maps.Copy(metaData, checksumMetaData(checksums))
+
+	// the copy keeps the stored content, so it always keeps its encryption and compression
+	maps.Copy(metaData, stored.metaData())
+
+	var content io.Reader = object
+
+	// the content of client-side encrypted files is bound to their path, so copies to another path are re-encrypted
+	if stored.envelope != nil && srcPath != dstPath {
+		var envelope map[string]string
+
+		content, envelope, err = stored.envelope.reseal(ctx, c.keyProvider, srcPath, dstPath, object)
+		if err != nil {
+			return nil, err
+		}
+
+		maps.Copy(metaData, envelope)
+	}
+
+	copyAttrs := func() *objectAttributes {
+		return &objectAttributes{
+			contentType:  cmp.Or(opts.contentType, attrs.contentType),
+			metaData:     canonicalMetaData(metaData),
+			tags:         attrs.tags,
+			modifiedDate: time.Now().UTC(),
+		}
+	}
+
+	if _, err := c.store.putObject(dstPath, content, copyAttrs); err != nil {
+		return nil, err
+	}
+
+	info := &UploadInfo{
+		Size:      source.Size,
+		Integrity: c.enabledIntegrity(checksums),
+	}
+
+	return info, nil
+}
+
+func (c *storeClient) AddLifeCycleRule(ctx context.Context, ruleID, folderPath string, daysToExpiry int) error {
+	const errMessage = "failed to add lifecycle rule: %w"
+
+	rule := &LifecycleRule{
+		ID:             ruleID,
+		Prefix:         folderPrefix(folderPath),
+		ExpirationDays: daysToExpiry,
+	}
+
+	if err := c.PutLifecycleRule(ctx, rule); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) GetLifecycleRules(ctx context.Context) ([]*LifecycleRule, error) {
+	const errMessage = "failed to get lifecycle rules: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	config, err := c.store.getLifecycle()
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return lifecycleRules(config), nil
+}
+
+func (c *storeClient) GetLifecycleRule(ctx context.Context, ruleID string) (*LifecycleRule, error) {
+	const errMessage = "failed to get lifecycle rule: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	config, err := c.store.getLifecycle()
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	rule, err := findLifecycleRule(config, ruleID)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return rule, nil
+}
+
+func (c *storeClient) PutLifecycleRule(ctx context.Context, rule *LifecycleRule) error {
+	const errMessage = "failed to put lifecycle rule: %w"
+
+	err := c.updateLifecycle(ctx, func(config *lifecycle.Configuration) error {
+		return putLifecycleRule(config, rule)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) RemoveLifecycleRule(ctx context.Context, ruleID string) error {
+	const errMessage = "failed to remove lifecycle rule: %w"
+
+	err := c.updateLifecycle(ctx, func(config *lifecycle.Configuration) error {
+		return removeLifecycleRule(config, ruleID)
+	})
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+// updateLifecycle applies the update to the current lifecycle configuration of the store.
+func (c *storeClient) updateLifecycle(ctx context.Context, update func(config *lifecycle.Configuration) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.lifecycleMtx.Lock()
+	defer c.lifecycleMtx.Unlock()
+
+	config, err := c.store.getLifecycle()
+	if err != nil {
+		return err
+	}
+
+	if err := update(config); err != nil {
+		return err
+	}
+
+	return c.store.setLifecycle(config)
+}
+
+// EnableVersioning is not supported, stores keep a single version of every file.
+func (c *storeClient) EnableVersioning(ctx context.Context) error {
+	const errMessage = "failed to enable versioning: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return fmt.Errorf(errMessage, ErrNotSupported)
+}
+
+// SuspendVersioning is not supported, stores keep a single version of every file.
+func (c *storeClient) SuspendVersioning(ctx context.Context) error {
+	const errMessage = "failed to suspend versioning: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return fmt.Errorf(errMessage, ErrNotSupported)
+}
+
+func (c *storeClient) GetVersioningStatus(ctx context.Context) (VersioningStatus, error) {
+	const errMessage = "failed to get versioning status: %w"
+
+	if err := ctx.Err(); err != nil {
+		return VersioningUnversioned, fmt.Errorf(errMessage, err)
+	}
+
+	return VersioningUnversioned, nil
+}
+
+// ListFileVersions returns the files as their only, latest version.
+func (c *storeClient) ListFileVersions(ctx context.Context, prefix string, options ...ListOption) iter.Seq2[*FileVersion, error] {
+	return func(yield func(*FileVersion, error) bool) {
+		for info, err := range c.ListFiles(ctx, prefix, options...) {
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to list file versions: %w", err))
+
+				return
+			}
+
+			if !yield(&FileVersion{FileInfo: *info, IsLatest: true}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// RestoreFileVersion is not supported, stores keep a single version of every file.
+func (c *storeClient) RestoreFileVersion(ctx context.Context, _, _ string) (*UploadInfo, error) {
+	const errMessage = "failed to restore file version: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return nil, fmt.Errorf(errMessage, ErrNotSupported)
+}
+
+// RotateFileKey re-wraps the data key of the file with the current key of the key provider.
+func (c *storeClient) RotateFileKey(ctx context.Context, path string) error {
+	const errMessage = "failed to rotate file key: %w"
+
+	if _, err := c.copyFile(ctx, path, path, &copyOptions{rotateKey: true}); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+// SetRetention is not supported, stores cannot lock files.
+func (c *storeClient) SetRetention(ctx context.Context, _ string, _ *Retention, _ ...LockOption) error {
+	const errMessage = "failed to set retention: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return fmt.Errorf(errMessage, ErrNotSupported)
+}
+
+// GetRetention returns no retention for existing files, since stores cannot lock files.
+func (c *storeClient) GetRetention(ctx context.Context, path string, options ...LockOption) (*Retention, error) {
+	const errMessage = "failed to get retention: %w"
+
+	if err := c.statLockedFile(ctx, path, options); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return nil, nil //nolint:nilnil // files without retention
+}
+
+// SetLegalHold is not supported, stores cannot lock files.
+func (c *storeClient) SetLegalHold(ctx context.Context, _ string, _ bool, _ ...LockOption) error {
+	const errMessage = "failed to set legal hold: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return fmt.Errorf(errMessage, ErrNotSupported)
+}
+
+// GetLegalHold returns no legal hold for existing files, since stores cannot lock files.
+func (c *storeClient) GetLegalHold(ctx context.Context, path string, options ...LockOption) (bool, error) {
+	const errMessage = "failed to get legal hold: %w"
+
+	if err := c.statLockedFile(ctx, path, options); err != nil {
+		return false, fmt.Errorf(errMessage, err)
+	}
+
+	return false, nil
+}
+
+// GetObjectLockConfig returns a disabled object lock configuration, since stores cannot lock files.
+func (c *storeClient) GetObjectLockConfig(ctx context.Context) (*ObjectLockConfig, error) {
+	const errMessage = "failed to get object lock config: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return &ObjectLockConfig{}, nil
+}
+
+// statLockedFile checks that the file whose lock is requested exists.
+func (c *storeClient) statLockedFile(ctx context.Context, path string, options []LockOption) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	opts := new(lockOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	if err := checkVersionID(opts.versionID); err != nil {
+		return err
+	}
+
+	_, err := c.store.statObject(path)
+
+	return err
+}
+
+func (c *storeClient) GetTags(ctx context.Context, path string, options ...TagOption) (map[string]string, error) {
+	const errMessage = "failed to get tags: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkVersionID(newTagOptions(options).versionID); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	attrs, err := c.store.statObject(path)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return attrs.tagMap(), nil
+}
+
+func (c *storeClient) PutTags(ctx context.Context, path string, tags map[string]string, options ...TagOption) error {
+	const errMessage = "failed to put tags: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkVersionID(newTagOptions(options).versionID); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if _, err := objectTags(tags); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := c.store.setTags(path, maps.Clone(tags)); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) RemoveTags(ctx context.Context, path string, options ...TagOption) error {
+	const errMessage = "failed to remove tags: %w"
+
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkVersionID(newTagOptions(options).versionID); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := c.store.setTags(path, nil); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+func (c *storeClient) CreateFileLink(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...FileLinkOption,
+) (*url.URL, error) {
+	const errMessage = "failed to create file link: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	link, err := c.store.objectLink(path, expiration, options)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return link, nil
+}
+
+// CreateUploadLink returns a link to the file, the store has no server accepting uploads through it.
+func (c *storeClient) CreateUploadLink(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...UploadLinkOption,
+) (*UploadLink, error) {
+	const errMessage = "failed to create upload link: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	opts, err := newUploadLinkOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	headers, err := opts.uploadLinkHeaders()
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	link, err := c.store.objectLink(path, expiration, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return &UploadLink{URL: link, Headers: headers}, nil
+}
+
+// CreateUploadPolicy returns a link to the file and the unsigned form data, the store has no server accepting uploads through it.
+func (c *storeClient) CreateUploadPolicy(
+	ctx context.Context,
+	path string,
+	expiration time.Duration,
+	options ...UploadLinkOption,
+) (*UploadPolicy, error) {
+	const errMessage = "failed to create upload policy: %w"
+
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	opts, err := newUploadLinkOptions(options)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if err := checkEncryption(opts.encryption); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	linkPath := path
+	if opts.pathPrefix {
+		// the prefix may be a folder, which is no valid object path
+		linkPath = strings.TrimSuffix(path, "/")
+	}
+
+	link, err := c.store.objectLink(linkPath, expiration, nil)
+	if err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	return &UploadPolicy{URL: link, FormData: opts.uploadPolicyFormData(path)}, nil
+}
+
+func (*storeClient) Close() {}
+
+func (*storeClient) IsOnline() bool {
+	return true
+}
+
+func (c *storeClient) IsHealthy() bool {
+	return c.IsOnline()
+}
+
+func (*storeClient) GetName() string {
+	return name
+}
+
+func (a *objectAttributes) fileInfo(path string) *FileInfo {
+	return &FileInfo{
+		Name:         pathpkg.Base(path),
+		Path:         path,
+		Size:         a.size,
+		ContentType:  a.contentType,
+		MetaData:     maps.Clone(a.metaData),
+		ModifiedDate: a.modifiedDate,
+	}
+}
+
+// tagMap returns a copy of the tags, which is empty if the object has no tags.
+func (a *objectAttributes) tagMap() map[string]string {
+	tags := make(map[string]string, len(a.tags))
+
+	maps.Copy(tags, a.tags)
+
+	return tags
+}
+
+// checkVersionID rejects requests for a specific version, since stores keep a single version of every file.
+func checkVersionID(versionID string) error {
+	if versionID != "" {
+		return fmt.Errorf("%w: file versions", ErrNotSupported)
+	}
+
+	return nil
+}
+
+// checkEncryption rejects encryption requested from a store, since stores keep the content as is.
+func checkEncryption(encryption *Encryption) error {
+	if encryption.Type() != EncryptionNone {
+		return fmt.Errorf("%w: server-side encryption", ErrNotSupported)
+	}
+
+	return nil
+}
+
+// uploadReader returns a reader for the content of the upload from the beginning.
+// When the upload size is set, exactly that many bytes are read.
+func uploadReader(upload *Upload) (io.Reader, error) {
+	const errMessage = "failed to read upload: %w"
+
+	if _, err := upload.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf(errMessage, err)
+	}
+
+	if upload.Size != nil && *upload.Size >= 0 {
+		return &exactReader{reader: upload, remaining: *upload.Size}, nil
+	}
+
+	return upload, nil
+}
+
+// exactReader reads exactly the remaining number of bytes and
+// fails with io.ErrUnexpectedEOF if the underlying reader ends early.
+type exactReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *exactReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	if err == io.EOF && r.remaining > 0 { //nolint:errorlint // io.EOF is never wrapped
+		return n, io.ErrUnexpectedEOF
+	}
+
+	return n, err
+}
+
+// writeLocalFile writes the content to the file system under the given localPath, creating missing folders.
+func writeLocalFile(localPath string, content io.Reader) error {
+	const errMessage = "failed to write local file: %w"
+
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o700); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	localFile, err := os.OpenFile(localPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if _, err := io.Copy(localFile, content); err != nil {
+		localFile.Close()
+
+		return fmt.Errorf(errMessage, err)
+	}
+
+	if err := localFile.Close(); err != nil {
+		return fmt.Errorf(errMessage, err)
+	}
+
+	return nil
+}
+
+// canonicalMetaData returns a copy of the given metadata with canonical keys, the way s3 reports user metadata.
+func canonicalMetaData(metaData map[string]string) map[string]string {
+	result := make(map[string]string, len(metaData))
+
+	for key, value := range metaData {
+		result[textproto.CanonicalMIMEHeaderKey(key)] = value
+	}
+
+	return result
+}
@@ -0,0 +1,666 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_StoreClients(t *testing.T) {
+	t.Parallel()
+
+	clients := map[string]func(t *testing.T, options ...s3.ClientOption) s3.Client{
+		"memory": getMemoryClient,
+		"local":  getLocalClient,
+	}
+
+	for clientName, newClient := range clients {
+		t.Run(clientName, func(t *testing.T) {
+			t.Parallel()
+
+			testStoreClient(t, newClient)
+		})
+	}
+}
+
+func Test_MemoryClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty bucket name", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewMemoryClient("")
+		require.ErrorIs(t, err, s3.ErrEmptyBucketName)
+	})
+
+	t.Run("default encryption", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewMemoryClient(bucketName, s3.WithDefaultEncryption(s3.SSES3()))
+		require.ErrorIs(t, err, s3.ErrNotSupported)
+	})
+
+	t.Run("is online", func(t *testing.T) {
+		t.Parallel()
+
+		client, err := s3.NewMemoryClient(bucketName, s3.WithHealthCheck(time.Second))
+		require.NoError(t, err)
+
+		require.True(t, client.IsOnline())
+
+		client.Close()
+	})
+
+	t.Run("create file link", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getMemoryClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-memory-create-file-link", testFile1Name)
+
+		link, err := s3Client.CreateFileLink(context.Background(), uploaded.filePath, time.Minute)
+		require.NoError(t, err)
+
+		require.Equal(t, "/"+bucketName+"/"+uploaded.filePath, link.Path)
+		require.Equal(t, "inline", link.Query().Get("response-content-disposition"))
+
+		link, err = s3Client.CreateFileLink(context.Background(), uploaded.filePath, time.Minute,
+			s3.WithAttachment(`report "final".pdf`),
+			s3.WithResponseContentType("application/pdf"),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, `attachment; filename="report _final_.pdf"; filename*=UTF-8''report%20%22final%22.pdf`,
+			link.Query().Get("response-content-disposition"))
+		require.Equal(t, "application/pdf", link.Query().Get("response-content-type"))
+
+		link, err = s3Client.CreateFileLink(context.Background(), uploaded.filePath, time.Minute, s3.WithInlineFileName("report.pdf"))
+		require.NoError(t, err)
+
+		require.Equal(t, `inline; filename="report.pdf"`, link.Query().Get("response-content-disposition"))
+	})
+}
+
+func Test_LocalClient(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty root path", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := s3.NewLocalClient("")
+		require.ErrorIs(t, err, s3.ErrEmptyRootPath)
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := getLocalClient(t)
+
+		_, err := s3Client.GetFile(context.Background(), "../outside")
+		require.ErrorIs(t, err, s3.ErrInvalidPath)
+
+		_, err = s3Client.GetFile(context.Background(), ".s3-client/objects")
+		require.ErrorIs(t, err, s3.ErrInvalidPath)
+	})
+
+	t.Run("invalid prefix", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		parentPath := t.TempDir()
+
+		err := os.WriteFile(filepath.Join(parentPath, "outside.txt"), []byte("outside"), 0o600)
+		require.NoError(t, err)
+
+		s3Client, err := s3.NewLocalClient(filepath.Join(parentPath, "root"))
+		require.NoError(t, err)
+
+		for _, prefix := range []string{"../", "folder/../../", "/etc/"} {
+			var listErr error
+
+			for info, err := range s3Client.ListFiles(ctx, prefix) {
+				require.Nil(t, info, prefix)
+
+				listErr = err
+			}
+
+			require.ErrorIs(t, listErr, s3.ErrInvalidPath, prefix)
+		}
+
+		removed, err := s3Client.RemoveDirectory(ctx, "..", s3.WithRemoveDirectoryDryRun())
+		require.ErrorIs(t, err, s3.ErrInvalidPath)
+		require.Empty(t, removed)
+
+		require.FileExists(t, filepath.Join(parentPath, "outside.txt"))
+	})
+
+	t.Run("file stored under root path", func(t *testing.T) {
+		t.Parallel()
+
+		rootPath := t.TempDir()
+
+		s3Client, err := s3.NewLocalClient(rootPath)
+		require.NoError(t, err)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-local-file", testFile1Name)
+
+		fileBytes, err := os.ReadFile(filepath.Join(rootPath, filepath.FromSlash(uploaded.filePath)))
+		require.NoError(t, err)
+		require.Equal(t, uploaded.content, fileBytes)
+
+		link, err := s3Client.CreateFileLink(context.Background(), uploaded.filePath, time.Minute)
+		require.NoError(t, err)
+
+		require.Equal(t, "file", link.Scheme)
+		require.Equal(t, filepath.ToSlash(filepath.Join(rootPath, filepath.FromSlash(uploaded.filePath))), link.Path)
+
+		err = s3Client.RemoveFile(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		require.NoDirExists(t, filepath.Join(rootPath, "test-local-file"))
+	})
+
+	t.Run("file without sidecar", func(t *testing.T) {
+		t.Parallel()
+
+		rootPath := t.TempDir()
+
+		s3Client, err := s3.NewLocalClient(rootPath, s3.WithCRC32CIntegritySupport(false))
+		require.NoError(t, err)
+
+		content := []byte("hello world")
+
+		err = os.WriteFile(filepath.Join(rootPath, "manual.txt"), content, 0o600)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(context.Background(), "manual.txt")
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+
+		require.Equal(t, content, fileContent)
+		require.Contains(t, file.Info().ContentType, contentType)
+	})
+
+	t.Run("partial failures", func(t *testing.T) {
+		t.Parallel()
+
+		const folder = "test-local-partial-failures"
+
+		rootPath := t.TempDir()
+
+		s3Client, err := s3.NewLocalClient(rootPath)
+		require.NoError(t, err)
+
+		paths := make([]string, 0, 3)
+
+		for range 3 {
+			paths = append(paths, uploadTestFileWithClient(t, s3Client, folder, testFile1Name).filePath)
+		}
+
+		slices.Sort(paths)
+
+		// a broken sidecar file fails the request of a single file
+		err = os.WriteFile(filepath.Join(rootPath, ".s3-client", "objects", filepath.FromSlash(paths[1])), []byte("{"), 0o600)
+		require.NoError(t, err)
+
+		_, err = s3Client.GetDirectoryInfos(context.Background(), folder)
+
+		var bulkErr *s3.BulkError
+
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, bulkErr.Failures, 1)
+		require.Equal(t, paths[1], bulkErr.Failures[0].Key)
+		require.Equal(t, s3.OperationGetFileInfo, bulkErr.Failures[0].Operation)
+		require.Equal(t, []string{paths[1]}, bulkErr.FailedKeys())
+		require.Equal(t, []string{paths[0], paths[2]}, bulkErr.Succeeded)
+
+		var syntaxErr *json.SyntaxError
+
+		require.ErrorAs(t, err, &syntaxErr)
+
+		fileInfos, err := s3Client.GetDirectoryInfos(context.Background(), folder, s3.WithGetDirectoryPartialResults())
+		require.ErrorAs(t, err, &bulkErr)
+		require.Len(t, fileInfos, 2)
+		require.Equal(t, paths[0], fileInfos[0].Path)
+		require.Equal(t, paths[2], fileInfos[1].Path)
+
+		files, err := s3Client.GetDirectory(context.Background(), folder, s3.WithGetDirectoryPartialResults())
+		require.ErrorAs(t, err, &bulkErr)
+		require.Equal(t, s3.OperationGetFile, bulkErr.Failures[0].Operation)
+		require.Len(t, files, 2)
+
+		for _, file := range files {
+			require.NoError(t, file.Close())
+		}
+
+		err = s3Client.DownloadDirectory(context.Background(), folder, t.TempDir(), true)
+		require.ErrorAs(t, err, &bulkErr)
+		require.ErrorAs(t, err, &syntaxErr)
+		require.Equal(t, s3.OperationDownloadFile, bulkErr.Failures[0].Operation)
+		require.Len(t, bulkErr.Succeeded, 2)
+	})
+
+	t.Run("auto integrity check detects corrupted file", func(t *testing.T) {
+		t.Parallel()
+
+		rootPath := t.TempDir()
+
+		s3Client, err := s3.NewLocalClient(rootPath)
+		require.NoError(t, err)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-local-corrupted", testFile1Name)
+
+		corrupted := bytes.ToUpper(uploaded.content)
+
+		err = os.WriteFile(filepath.Join(rootPath, filepath.FromSlash(uploaded.filePath)), corrupted, 0o600)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, corrupted, fileContent)
+
+		file, err = s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(file)
+		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
+		require.ErrorIs(t, file.Close(), s3.ErrChecksumMismatch)
+	})
+}
+
+func testStoreClient(t *testing.T, newClient func(t *testing.T, options ...s3.ClientOption) s3.Client) {
+	t.Helper()
+
+	t.Run("upload and get file", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-get-file", testFile1Name)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+
+		fileInfo := file.Info()
+
+		expectedChecksum, err := s3.GenerateCheckSumCRC32C(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.content, fileContent)
+		require.Equal(t, uploaded.lenTestFile, fileInfo.Size)
+		require.Equal(t, uploaded.contentType, fileInfo.ContentType)
+		require.Equal(t, uploaded.fileName, fileInfo.Name)
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumCRC32C)
+		require.False(t, fileInfo.ModifiedDate.IsZero())
+	})
+
+	t.Run("get file info with md5 integrity support", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t, s3.WithCRC32CIntegritySupport(false), s3.WithMD5IntegritySupport(true))
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-get-file-info", testFile1Name)
+
+		expectedChecksum, err := s3.GenerateCheckSumMD5(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		fileInfo, err := s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.lenTestFile, fileInfo.Size)
+		require.Equal(t, uploaded.metaData, fileInfo.MetaData)
+		require.Equal(t, expectedChecksum, fileInfo.ChecksumMD5)
+		require.Empty(t, fileInfo.ChecksumCRC32C)
+	})
+
+	t.Run("upload file reads content once", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t, s3.WithMD5IntegritySupport(true))
+
+		testUploadReadsContentOnce(t, s3Client, "test-store-upload")
+	})
+
+	t.Run("upload stream", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		testUploadStream(t, s3Client, "test-store-upload-stream")
+	})
+
+	t.Run("multiple checksum algorithms", func(t *testing.T) {
+		t.Parallel()
+
+		testMultipleChecksumAlgorithms(t, newClient, "test-store-checksum-algorithms")
+	})
+
+	t.Run("integrity check while reading", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-integrity", testFile1Name)
+
+		expectedChecksum, err := s3.GenerateCheckSumMD5(bytes.NewReader(uploaded.content))
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckMD5(expectedChecksum))
+		require.NoError(t, err)
+		require.Empty(t, file.Info().ChecksumMD5)
+
+		fileContent, err := io.ReadAll(file)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		require.Equal(t, uploaded.content, fileContent)
+		require.Equal(t, expectedChecksum, file.Info().ChecksumMD5)
+
+		file, err = s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckMD5("invalid-checksum"))
+		require.NoError(t, err)
+
+		_, err = io.ReadAll(file)
+		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
+		require.ErrorIs(t, file.Close(), s3.ErrChecksumMismatch)
+	})
+
+	t.Run("auto integrity check", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t, s3.WithIntegritySupport(s3.ChecksumAlgorithmSHA256, true))
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-integrity", testFile2Name)
+
+		file, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		fileContent, err := file.Bytes()
+		require.NoError(t, err)
+
+		require.Equal(t, uploaded.content, fileContent)
+		require.NotEmpty(t, file.Info().Checksum(s3.ChecksumAlgorithmSHA256))
+	})
+
+	t.Run("invalid crc32c checksum", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-integrity", testFile1Name)
+
+		_, err := s3Client.GetFile(context.Background(), uploaded.filePath, s3.WithIntegrityCheckCRC32C("invalid-checksum"))
+		require.ErrorIs(t, err, s3.ErrChecksumMismatch)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		_, err := s3Client.GetFile(context.Background(), uuid.NewString())
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		_, err = s3Client.GetFileInfo(context.Background(), uuid.NewString())
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		err = s3Client.DownloadFile(context.Background(), uuid.NewString(), t.TempDir()+"/file")
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+
+	t.Run("list files", func(t *testing.T) {
+		t.Parallel()
+
+		testListFiles(t, newClient, "test-store-list-files")
+	})
+
+	t.Run("bounded concurrency", func(t *testing.T) {
+		t.Parallel()
+
+		testBoundedConcurrency(t, newClient, "test-store-bounded-concurrency")
+	})
+
+	t.Run("upload directory", func(t *testing.T) {
+		t.Parallel()
+
+		testUploadDirectory(t, newClient, "test-store-upload-directory")
+	})
+
+	t.Run("sync", func(t *testing.T) {
+		t.Parallel()
+
+		testSync(t, newClient, "test-store-sync")
+	})
+
+	t.Run("remove directory", func(t *testing.T) {
+		t.Parallel()
+
+		testRemoveDirectory(t, newClient, "test-store-remove-directory")
+	})
+
+	t.Run("copy file", func(t *testing.T) {
+		t.Parallel()
+
+		testCopyFile(t, newClient, "test-store-copy-file")
+	})
+
+	t.Run("copy file into another bucket", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-copy-file", testFile1Name)
+
+		_, err := s3Client.CopyFile(context.Background(), uploaded.filePath, uploaded.filePath, s3.WithDestinationBucket("other-bucket"))
+		require.ErrorIs(t, err, s3.ErrNotSupported)
+	})
+
+	t.Run("upload links", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		testUploadLinkOptions(t, s3Client)
+
+		link, err := s3Client.CreateUploadLink(context.Background(), "test-store-upload-link/file", time.Minute,
+			s3.WithUploadLinkContentType(contentType),
+			s3.WithUploadLinkChecksum(s3.ChecksumAlgorithmMD5, "checksum"),
+		)
+		require.NoError(t, err)
+		require.Equal(t, contentType, link.Headers.Get("Content-Type"))
+		require.Equal(t, "checksum", link.Headers.Get("X-Amz-Meta-Checksum-Md5"))
+
+		policy, err := s3Client.CreateUploadPolicy(context.Background(), "test-store-upload-link/", time.Minute,
+			s3.WithPathPrefix(),
+			s3.WithRequiredMetaData(headerFileName),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"key": "test-store-upload-link/", "x-amz-meta-filename": ""}, policy.FormData)
+	})
+
+	t.Run("file versions", func(t *testing.T) {
+		t.Parallel()
+
+		testStoreFileVersions(t, newClient(t))
+	})
+
+	t.Run("object lock", func(t *testing.T) {
+		t.Parallel()
+
+		testStoreObjectLock(t, newClient(t))
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		t.Parallel()
+
+		testTags(t, newClient(t))
+	})
+
+	t.Run("encryption", func(t *testing.T) {
+		t.Parallel()
+
+		testStoreEncryption(t, newClient(t))
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		t.Parallel()
+
+		testCompression(t, newClient, "test-store-compression")
+	})
+
+	t.Run("lifecycle rules", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		testLifecycleRules(t, s3Client)
+		testLifecycleRuleActions(t, s3Client)
+
+		rules, err := s3Client.GetLifecycleRules(context.Background())
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+	})
+
+	t.Run("get directory", func(t *testing.T) {
+		t.Parallel()
+
+		const folder = "test-store-get-directory"
+
+		s3Client := newClient(t)
+
+		uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+		uploadTestFileWithClient(t, s3Client, folder, testFile2Name)
+		uploadTestFileWithClient(t, s3Client, "other-folder", testFile1Name)
+
+		files, err := s3Client.GetDirectory(context.Background(), folder)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		fileInfos, err := s3Client.GetDirectoryInfos(context.Background(), folder)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, 2)
+	})
+
+	t.Run("download directory", func(t *testing.T) {
+		t.Parallel()
+
+		const folder = "test-store-download-directory"
+
+		s3Client := newClient(t)
+
+		uploaded1 := uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+		uploaded2 := uploadTestFileWithClient(t, s3Client, folder+"/sub", testFile2Name)
+
+		localFolder := t.TempDir()
+
+		err := s3Client.DownloadDirectory(context.Background(), folder, localFolder, false)
+		require.NoError(t, err)
+
+		fileBytes, err := os.ReadFile(localFolder + "/" + uploaded1.fileName)
+		require.NoError(t, err)
+		require.Equal(t, uploaded1.content, fileBytes)
+
+		require.NoFileExists(t, localFolder+"/sub/"+uploaded2.fileName)
+
+		err = s3Client.DownloadDirectory(context.Background(), folder, localFolder, true)
+		require.NoError(t, err)
+
+		fileBytes, err = os.ReadFile(localFolder + "/sub/" + uploaded2.fileName)
+		require.NoError(t, err)
+		require.Equal(t, uploaded2.content, fileBytes)
+
+		// the version of a single file does not apply to the files of the directory
+		localFolder = t.TempDir()
+
+		err = s3Client.DownloadDirectory(context.Background(), folder, localFolder, true, s3.WithDownloadVersionID("version"))
+		require.NoError(t, err)
+
+		fileBytes, err = os.ReadFile(localFolder + "/sub/" + uploaded2.fileName)
+		require.NoError(t, err)
+		require.Equal(t, uploaded2.content, fileBytes)
+	})
+
+	t.Run("remove file", func(t *testing.T) {
+		t.Parallel()
+
+		s3Client := newClient(t)
+
+		uploaded := uploadTestFileWithClient(t, s3Client, "test-store-remove-file", testFile1Name)
+
+		err := s3Client.RemoveFile(context.Background(), uploaded.filePath)
+		require.NoError(t, err)
+
+		_, err = s3Client.GetFileInfo(context.Background(), uploaded.filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+	})
+
+	t.Run("concurrent uploads", func(t *testing.T) {
+		t.Parallel()
+
+		const (
+			folder  = "test-store-concurrent"
+			uploads = 50
+		)
+
+		s3Client := newClient(t)
+
+		wg := new(sync.WaitGroup)
+
+		for range uploads {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				uploadTestFileWithClient(t, s3Client, folder, testFile1Name)
+			}()
+		}
+
+		wg.Wait()
+
+		fileInfos, err := s3Client.GetDirectoryInfos(context.Background(), folder)
+		require.NoError(t, err)
+		require.Len(t, fileInfos, uploads)
+	})
+}
+
+func getMemoryClient(t *testing.T, options ...s3.ClientOption) s3.Client {
+	t.Helper()
+
+	s3Client, err := s3.NewMemoryClient(bucketName, options...)
+	require.NoError(t, err)
+
+	t.Cleanup(s3Client.Close)
+
+	return s3Client
+}
+
+func getLocalClient(t *testing.T, options ...s3.ClientOption) s3.Client {
+	t.Helper()
+
+	s3Client, err := s3.NewLocalClient(t.TempDir(), options...)
+	require.NoError(t, err)
+
+	t.Cleanup(s3Client.Close)
+
+	return s3Client
+}
@@ -0,0 +1,382 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SyncDirection defines which side of a sync is the source.
+type SyncDirection int
+
+const (
+	// SyncUpload makes the s3 prefix match the local folder.
+	SyncUpload SyncDirection = iota
+	// SyncDownload makes the local folder match the s3 prefix.
+	SyncDownload
+)
+
+// SyncActionType is the kind of change a sync applies to a file of the destination.
+type SyncActionType string
+
+const (
+	// SyncCreate copies a file missing on the destination.
+	SyncCreate SyncActionType = "create"
+	// SyncUpdate copies a file which differs on the destination.
+	SyncUpdate SyncActionType = "update"
+	// SyncDelete deletes a file of the destination missing on the source.
+	SyncDelete SyncActionType = "delete"
+)
+
+// SyncReason describes why a file is synced.
+type SyncReason string
+
+const (
+	// SyncReasonMissing is the reason of files missing on the destination.
+	SyncReasonMissing SyncReason = "missing on destination"
+	// SyncReasonSize is the reason of files with a different size.
+	SyncReasonSize SyncReason = "size differs"
+	// SyncReasonChecksum is the reason of files with a different checksum.
+	SyncReasonChecksum SyncReason = "checksum differs"
+	// SyncReasonModified is the reason of files modified after the destination file, when no checksum is stored.
+	SyncReasonModified SyncReason = "source is newer"
+	// SyncReasonExtraneous is the reason of files missing on the source.
+	SyncReasonExtraneous SyncReason = "missing on source"
+)
+
+// SyncAction is a change a sync applies to a single file of the destination.
+type SyncAction struct {
+	// Path is the slash separated path relative to the local folder and the s3 prefix.
+	Path   string
+	Type   SyncActionType
+	Reason SyncReason
+	// Size and ModifiedDate describe the source file. Both are empty for deletions.
+	Size         int64
+	ModifiedDate time.Time
+}
+
+// SyncPlan describes the changes required to make the destination match the source.
+// A plan is created by PlanSync and executed by ApplySync.
+type SyncPlan struct {
+	Direction SyncDirection
+	LocalPath string
+	Prefix    string
+	// Actions are the changes to apply, sorted by path.
+	Actions []*SyncAction
+	// Unchanged contains the paths of the files present on both sides with equal content, sorted by path.
+	Unchanged []string
+
+	options bulkOptions
+}
+
+// syncFile describes a file of either side of a sync.
+type syncFile struct {
+	size         int64
+	modifiedDate time.Time
+	integrity    Integrity
+}
+
+// planSync compares the local folder with the s3 prefix. Files of equal size are compared using the checksums
+// stored in s3, so unchanged files are detected without downloading them. Only if no checksum is stored,
+// the modified dates are compared.
+func planSync(
+	ctx context.Context,
+	client Client,
+	algorithms []ChecksumAlgorithm,
+	localPath, prefix string,
+	direction SyncDirection,
+	options ...SyncOption,
+) (*SyncPlan, error) {
+	opts := new(syncOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	plan := &SyncPlan{
+		Direction: direction,
+		LocalPath: localPath,
+		Prefix:    prefix,
+		Actions:   make([]*SyncAction, 0),
+		Unchanged: make([]string, 0),
+		options:   opts.bulkOptions,
+	}
+
+	// a missing local folder is synced like an empty folder, unless it is the source
+	localFiles, err := listLocalSyncFiles(localPath, direction == SyncDownload)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteFiles, err := listRemoteSyncFiles(ctx, client, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	source, destination := localFiles, remoteFiles
+
+	if direction == SyncDownload {
+		source, destination = remoteFiles, localFiles
+	}
+
+	for _, relPath := range sortedKeys(source, destination) {
+		sourceFile, inSource := source[relPath]
+		_, inDestination := destination[relPath]
+
+		switch {
+		case !inSource:
+			if opts.delete {
+				plan.Actions = append(plan.Actions, &SyncAction{Path: relPath, Type: SyncDelete, Reason: SyncReasonExtraneous})
+			}
+		case !inDestination:
+			plan.Actions = append(plan.Actions, newSyncAction(relPath, SyncCreate, SyncReasonMissing, sourceFile))
+		default:
+			localFile := filepath.Join(localPath, filepath.FromSlash(relPath))
+
+			reason, err := compareSyncFiles(localFile, algorithms, localFiles[relPath], remoteFiles[relPath], direction)
+			if err != nil {
+				return nil, err
+			}
+
+			if reason == "" {
+				plan.Unchanged = append(plan.Unchanged, relPath)
+
+				continue
+			}
+
+			plan.Actions = append(plan.Actions, newSyncAction(relPath, SyncUpdate, reason, sourceFile))
+		}
+	}
+
+	return plan, nil
+}
+
+func newSyncAction(relPath string, actionType SyncActionType, reason SyncReason, source *syncFile) *SyncAction {
+	return &SyncAction{
+		Path:         relPath,
+		Type:         actionType,
+		Reason:       reason,
+		Size:         source.size,
+		ModifiedDate: source.modifiedDate,
+	}
+}
+
+// compareSyncFiles returns the reason why the file has to be synced, or an empty reason if the file is unchanged.
+func compareSyncFiles(
+	localFile string,
+	algorithms []ChecksumAlgorithm,
+	local, remote *syncFile,
+	direction SyncDirection,
+) (SyncReason, error) {
+	if local.size != remote.size {
+		return SyncReasonSize, nil
+	}
+
+	for _, algorithm := range algorithms {
+		remoteChecksum := remote.integrity.Checksum(algorithm)
+		if remoteChecksum == "" {
+			continue
+		}
+
+		localChecksum, err := localChecksum(localFile, algorithm)
+		if err != nil {
+			return "", err
+		}
+
+		if localChecksum != remoteChecksum {
+			return SyncReasonChecksum, nil
+		}
+
+		return "", nil
+	}
+
+	source, destination := local, remote
+
+	if direction == SyncDownload {
+		source, destination = remote, local
+	}
+
+	if source.modifiedDate.After(destination.modifiedDate) {
+		return SyncReasonModified, nil
+	}
+
+	return "", nil
+}
+
+func localChecksum(localFile string, algorithm ChecksumAlgorithm) (string, error) {
+	content, err := os.Open(localFile)
+	if err != nil {
+		return "", err
+	}
+
+	defer content.Close()
+
+	return GenerateCheckSum(algorithm, content)
+}
+
+func listLocalSyncFiles(localPath string, allowMissing bool) (map[string]*syncFile, error) {
+	files := make(map[string]*syncFile)
+
+	if _, err := os.Stat(localPath); allowMissing && errors.Is(err, os.ErrNotExist) {
+		return files, nil
+	}
+
+	for relPath, err := range new(localFilter).localFiles(localPath) {
+		if err != nil {
+			return nil, err
+		}
+
+		stat, err := os.Stat(filepath.Join(localPath, filepath.FromSlash(relPath)))
+		if err != nil {
+			return nil, err
+		}
+
+		files[relPath] = &syncFile{
+			size:         stat.Size(),
+			modifiedDate: stat.ModTime(),
+		}
+	}
+
+	return files, nil
+}
+
+func listRemoteSyncFiles(ctx context.Context, client Client, prefix string) (map[string]*syncFile, error) {
+	keyPrefix := ""
+
+	if prefix != "" {
+		keyPrefix = folderPrefix(prefix)
+	}
+
+	files := make(map[string]*syncFile)
+
+	for info, err := range client.ListFiles(ctx, keyPrefix, WithFullFileInfo()) {
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.HasSuffix(info.Path, "/") {
+			continue // folder markers
+		}
+
+		files[strings.TrimPrefix(info.Path, keyPrefix)] = &syncFile{
+			size:         info.Size,
+			modifiedDate: info.ModifiedDate,
+			integrity:    info.Integrity,
+		}
+	}
+
+	return files, nil
+}
+
+func sortedKeys(source, destination map[string]*syncFile) []string {
+	keys := make([]string, 0, len(source)+len(destination))
+
+	for key := range source {
+		keys = append(keys, key)
+	}
+
+	for key := range destination {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return slices.Compact(keys)
+}
+
+// applySync executes the actions of the plan. If some of the files failed, a BulkError is returned.
+func applySync(ctx context.Context, client Client, clientConcurrency int, plan *SyncPlan) error {
+	keyPrefix := ""
+
+	if plan.Prefix != "" {
+		keyPrefix = folderPrefix(plan.Prefix)
+	}
+
+	actions := make(map[string]*SyncAction, len(plan.Actions))
+
+	for _, action := range plan.Actions {
+		actions[action.Path] = action
+	}
+
+	listKeys := func(context.Context) iter.Seq2[string, error] {
+		return func(yield func(string, error) bool) {
+			for _, action := range plan.Actions {
+				if !yield(action.Path, nil) {
+					return
+				}
+			}
+		}
+	}
+
+	results, err := runBulk(
+		ctx,
+		listKeys,
+		plan.options.concurrencyLimit(clientConcurrency),
+		plan.options.failFast,
+		func(ctx context.Context, relPath string) (*SyncAction, error) {
+			action := actions[relPath]
+
+			localFile := filepath.Join(plan.LocalPath, filepath.FromSlash(relPath))
+
+			// the paths of downloads are keys of s3, which must not address files outside of the local folder
+			validPath := filepath.IsLocal(filepath.FromSlash(relPath)) && !strings.HasSuffix(relPath, "/")
+
+			if plan.Direction == SyncDownload && !validPath {
+				return action, fmt.Errorf("%w: '%s'", ErrInvalidPath, relPath)
+			}
+
+			return action, applySyncAction(ctx, client, plan.Direction, action, localFile, keyPrefix+relPath)
+		},
+	)
+
+	if _, err := collectResults(results, err, OperationUploadFile, false); err != nil {
+		var bulkErr *BulkError
+
+		if errors.As(err, &bulkErr) {
+			for _, failure := range bulkErr.Failures {
+				failure.Operation = syncOperation(plan.Direction, actions[failure.Key])
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func applySyncAction(ctx context.Context, client Client, direction SyncDirection, action *SyncAction, localFile, key string) error {
+	switch {
+	case action.Type == SyncDelete && direction == SyncUpload:
+		return client.RemoveFile(ctx, key)
+	case action.Type == SyncDelete:
+		return os.Remove(localFile)
+	case direction == SyncUpload:
+		_, err := uploadLocalFile(ctx, localFile, key, client.UploadFile, nil)
+
+		return err
+	default:
+		if err := client.DownloadFile(ctx, key, localFile); err != nil {
+			return err
+		}
+
+		// the modified date of the s3 file is kept, so the next sync detects the file as unchanged
+		return os.Chtimes(localFile, time.Time{}, action.ModifiedDate)
+	}
+}
+
+func syncOperation(direction SyncDirection, action *SyncAction) Operation {
+	switch {
+	case action.Type == SyncDelete:
+		return OperationRemoveFile
+	case direction == SyncUpload:
+		return OperationUploadFile
+	default:
+		return OperationDownloadFile
+	}
+}
@@ -0,0 +1,27 @@
+package s3 //nolint:revive // package name matches folder name
+
+import (
+	"fmt"
+
+	"github.com/minio/minio-go/v7/pkg/tags"
+)
+
+// objectTags validates the tags against the s3 limits and converts them.
+func objectTags(tagMap map[string]string) (*tags.Tags, error) {
+	objectTags, err := tags.MapToObjectTags(tagMap)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTags, err)
+	}
+
+	return objectTags, nil
+}
+
+func newTagOptions(options []TagOption) *tagOptions {
+	opts := new(tagOptions)
+
+	for i := range options {
+		options[i](opts)
+	}
+
+	return opts
+}
@@ -0,0 +1,95 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Tags(t *testing.T) {
+	t.Parallel()
+
+	testTags(t, getS3Client(t))
+}
+
+func testTags(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	folder := "test-tags/" + uuid.NewString()
+	filePath := folder + "/tagged"
+
+	upload := s3.NewUpload(strings.NewReader("tagged"), nil, filePath, contentType, nil)
+	upload.Tags = map[string]string{"project": "apollo", "temporary": "true"}
+
+	_, err := s3Client.UploadFile(ctx, upload)
+	require.NoError(t, err)
+
+	_, err = s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader("untagged"), nil, folder+"/untagged", contentType, nil))
+	require.NoError(t, err)
+
+	tags, err := s3Client.GetTags(ctx, filePath)
+	require.NoError(t, err)
+	require.Equal(t, upload.Tags, tags)
+
+	copied, err := s3Client.CopyFile(ctx, filePath, folder+"/copied")
+	require.NoError(t, err)
+	require.NotNil(t, copied)
+
+	tags, err = s3Client.GetTags(ctx, folder+"/copied")
+	require.NoError(t, err)
+	require.Equal(t, upload.Tags, tags)
+
+	err = s3Client.PutTags(ctx, filePath, map[string]string{"project": "gemini"})
+	require.NoError(t, err)
+
+	tags, err = s3Client.GetTags(ctx, filePath)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"project": "gemini"}, tags)
+
+	listed := make(map[string]map[string]string)
+
+	for info, err := range s3Client.ListFiles(ctx, folder+"/", s3.WithFileTags()) {
+		require.NoError(t, err)
+
+		listed[info.Name] = info.Tags
+	}
+
+	require.Equal(t, map[string]map[string]string{
+		"copied":   {"project": "apollo", "temporary": "true"},
+		"tagged":   {"project": "gemini"},
+		"untagged": {},
+	}, listed)
+
+	for info, err := range s3Client.ListFiles(ctx, folder+"/") {
+		require.NoError(t, err)
+		require.Nil(t, info.Tags)
+	}
+
+	err = s3Client.RemoveTags(ctx, filePath)
+	require.NoError(t, err)
+
+	tags, err = s3Client.GetTags(ctx, filePath)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+
+	err = s3Client.PutTags(ctx, filePath, map[string]string{"": "empty key"})
+	require.ErrorIs(t, err, s3.ErrInvalidTags)
+
+	upload = s3.NewUpload(strings.NewReader("invalid"), nil, folder+"/invalid", contentType, nil)
+	upload.Tags = map[string]string{strings.Repeat("k", 129): "too long"}
+
+	_, err = s3Client.UploadFile(ctx, upload)
+	require.ErrorIs(t, err, s3.ErrInvalidTags)
+
+	_, err = s3Client.GetTags(ctx, folder+"/missing")
+	require.ErrorIs(t, err, s3.ErrNotFound)
+
+	err = s3Client.PutTags(ctx, folder+"/missing", map[string]string{"project": "apollo"})
+	require.ErrorIs(t, err, s3.ErrNotFound)
+}
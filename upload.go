@@ -9,6 +9,8 @@ type Upload struct {
 	ContentType string
 	MetaData    map[string]string
 	Size        *int64
+	// Tags classify the file, e.g. for lifecycle rules. Unlike the metadata, tags can be changed after the upload.
+	Tags map[string]string
 }
 
 // NewUpload creates a new Upload instance.
@@ -25,5 +27,37 @@ func NewUpload(data io.ReadSeeker, size *int64, path, contentType string, metaDa
 // UploadInfo contains information about the uploaded file.
 type UploadInfo struct {
 	Size int64
+	// VersionID is the version created by the upload in a versioned bucket, it is empty in unversioned buckets.
+	VersionID string
 	Integrity
 }
+
+// StreamUpload represents data of unknown size from a non-seekable reader, e.g. a request body or a pipe,
+// that can be uploaded to the s3.
+type StreamUpload struct {
+	io.Reader
+	Path        string
+	ContentType string
+	MetaData    map[string]string
+	// Tags classify the file, e.g. for lifecycle rules. Unlike the metadata, tags can be changed after the upload.
+	Tags map[string]string
+}
+
+// NewStreamUpload creates a new StreamUpload instance.
+func NewStreamUpload(data io.Reader, path, contentType string, metaData map[string]string) *StreamUpload {
+	return &StreamUpload{
+		Reader:      data,
+		Path:        path,
+		ContentType: contentType,
+		MetaData:    metaData,
+	}
+}
+
+// UploadedFile contains information about a file uploaded by UploadDirectory.
+type UploadedFile struct {
+	// LocalPath is the path of the uploaded file in the file system.
+	LocalPath string
+	// Path is the s3 path the file has been uploaded to.
+	Path string
+	UploadInfo
+}
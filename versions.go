@@ -0,0 +1,22 @@
+package s3 //nolint:revive // package name matches folder name
+
+// VersioningStatus is the versioning state of a bucket.
+type VersioningStatus string
+
+const (
+	// VersioningUnversioned is the state of buckets which never had versioning enabled.
+	VersioningUnversioned VersioningStatus = ""
+	// VersioningEnabled keeps all versions of the files.
+	VersioningEnabled VersioningStatus = "Enabled"
+	// VersioningSuspended keeps the existing versions, but new uploads replace the null version.
+	VersioningSuspended VersioningStatus = "Suspended"
+)
+
+// FileVersion is a version of a file or a delete marker.
+type FileVersion struct {
+	FileInfo
+	// IsLatest reports whether the version is the current version of the file.
+	IsLatest bool
+	// IsDeleteMarker reports whether the version marks the file as deleted. Delete markers have no content.
+	IsDeleteMarker bool
+}
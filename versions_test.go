@@ -0,0 +1,196 @@
+package s3_test //nolint:revive // package name matches folder name
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Clarilab/s3-client/v4"
+	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FileVersions(t *testing.T) {
+	t.Parallel()
+
+	const versionedBucketName = "test-file-versions"
+
+	ctx := context.Background()
+
+	err := minioClient.MakeBucket(ctx, versionedBucketName, minio.MakeBucketOptions{})
+	require.NoError(t, err)
+
+	clientDetails := &s3.ClientDetails{
+		Host:         s3URL,
+		AccessKey:    s3User,
+		AccessSecret: s3Pwd,
+		BucketName:   versionedBucketName,
+	}
+
+	s3Client, err := s3.NewClient(clientDetails)
+	require.NoError(t, err)
+
+	t.Cleanup(s3Client.Close)
+
+	status, err := s3Client.GetVersioningStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, s3.VersioningUnversioned, status)
+
+	err = s3Client.EnableVersioning(ctx)
+	require.NoError(t, err)
+
+	status, err = s3Client.GetVersioningStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, s3.VersioningEnabled, status)
+
+	const folder = "test-file-versions"
+
+	filePath := folder + "/" + uuid.NewString()
+
+	first, err := s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader("first"), nil, filePath, contentType, nil))
+	require.NoError(t, err)
+	require.NotEmpty(t, first.VersionID)
+
+	second, err := s3Client.UploadFile(ctx, s3.NewUpload(strings.NewReader("second"), nil, filePath, contentType, nil))
+	require.NoError(t, err)
+	require.NotEqual(t, first.VersionID, second.VersionID)
+
+	// recording the checksums does not leave versions without checksums behind
+	versions := listFileVersions(t, s3Client, folder)
+	require.Equal(t, []string{second.VersionID, first.VersionID}, versionIDs(versions))
+	require.True(t, versions[0].IsLatest)
+	require.False(t, versions[1].IsLatest)
+
+	t.Run("get version", func(t *testing.T) {
+		file, err := s3Client.GetFile(ctx, filePath, s3.WithVersionID(first.VersionID), s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		content, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "first", string(content))
+		require.Equal(t, first.VersionID, file.Info().VersionID)
+
+		info, err := s3Client.GetFileInfo(ctx, filePath, s3.WithFileInfoVersionID(first.VersionID))
+		require.NoError(t, err)
+		require.Equal(t, first.VersionID, info.VersionID)
+		require.Equal(t, first.Checksum(s3.ChecksumAlgorithmCRC32C), info.Checksum(s3.ChecksumAlgorithmCRC32C))
+
+		localPath := filepath.Join(t.TempDir(), "first")
+
+		err = s3Client.DownloadFile(ctx, filePath, localPath, s3.WithDownloadVersionID(first.VersionID))
+		require.NoError(t, err)
+
+		content, err = os.ReadFile(localPath)
+		require.NoError(t, err)
+		require.Equal(t, "first", string(content))
+
+		_, err = s3Client.GetFile(ctx, filePath, s3.WithVersionID(uuid.NewString()))
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		// the version of a single file does not apply to the files of the directory
+		localFolder := t.TempDir()
+
+		err = s3Client.DownloadDirectory(ctx, folder, localFolder, true, s3.WithDownloadVersionID(first.VersionID))
+		require.NoError(t, err)
+
+		content, err = os.ReadFile(filepath.Join(localFolder, strings.TrimPrefix(filePath, folder+"/")))
+		require.NoError(t, err)
+		require.Equal(t, "second", string(content))
+	})
+
+	t.Run("restore version", func(t *testing.T) {
+		restored, err := s3Client.RestoreFileVersion(ctx, filePath, first.VersionID)
+		require.NoError(t, err)
+
+		file, err := s3Client.GetFile(ctx, filePath, s3.WithAutoIntegrityCheck())
+		require.NoError(t, err)
+
+		content, err := file.Bytes()
+		require.NoError(t, err)
+		require.Equal(t, "first", string(content))
+		require.Equal(t, restored.VersionID, file.Info().VersionID)
+
+		versions := listFileVersions(t, s3Client, folder)
+		require.Equal(t, []string{restored.VersionID, second.VersionID, first.VersionID}, versionIDs(versions))
+	})
+
+	t.Run("delete marker", func(t *testing.T) {
+		err := s3Client.RemoveFile(ctx, filePath)
+		require.NoError(t, err)
+
+		_, err = s3Client.GetFileInfo(ctx, filePath)
+		require.ErrorIs(t, err, s3.ErrNotFound)
+
+		versions := listFileVersions(t, s3Client, folder)
+		require.True(t, versions[0].IsLatest)
+		require.True(t, versions[0].IsDeleteMarker)
+
+		err = s3Client.RemoveFile(ctx, filePath, s3.WithRemoveVersionID(versions[0].VersionID))
+		require.NoError(t, err)
+
+		_, err = s3Client.GetFileInfo(ctx, filePath)
+		require.NoError(t, err)
+	})
+
+	t.Run("suspend versioning", func(t *testing.T) {
+		err := s3Client.SuspendVersioning(ctx)
+		require.NoError(t, err)
+
+		status, err := s3Client.GetVersioningStatus(ctx)
+		require.NoError(t, err)
+		require.Equal(t, s3.VersioningSuspended, status)
+	})
+}
+
+func testStoreFileVersions(t *testing.T, s3Client s3.Client) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	status, err := s3Client.GetVersioningStatus(ctx)
+	require.NoError(t, err)
+	require.Equal(t, s3.VersioningUnversioned, status)
+
+	err = s3Client.EnableVersioning(ctx)
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	uploaded := uploadTestFileWithClient(t, s3Client, "test-store-file-versions", testFile1Name)
+
+	versions := listFileVersions(t, s3Client, "test-store-file-versions")
+	require.Len(t, versions, 1)
+	require.Equal(t, uploaded.filePath, versions[0].Path)
+	require.True(t, versions[0].IsLatest)
+
+	_, err = s3Client.GetFile(ctx, uploaded.filePath, s3.WithVersionID("version"))
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+
+	_, err = s3Client.RestoreFileVersion(ctx, uploaded.filePath, "version")
+	require.ErrorIs(t, err, s3.ErrNotSupported)
+}
+
+func listFileVersions(t *testing.T, s3Client s3.Client, prefix string) []*s3.FileVersion {
+	t.Helper()
+
+	versions := make([]*s3.FileVersion, 0)
+
+	for version, err := range s3Client.ListFileVersions(context.Background(), prefix+"/") {
+		require.NoError(t, err)
+
+		versions = append(versions, version)
+	}
+
+	return versions
+}
+
+func versionIDs(versions []*s3.FileVersion) []string {
+	ids := make([]string, 0, len(versions))
+
+	for _, version := range versions {
+		ids = append(ids, version.VersionID)
+	}
+
+	return ids
+}